@@ -0,0 +1,135 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Alias is the name of the series in charts, its key unless an alias was set.
+// Operations deriving a new key, like transforms, drop the alias.
+func (ts *TimeSeries) Alias() string {
+	if ts.alias == "" {
+		return ts.key
+	}
+	return ts.alias
+}
+
+// SetAlias sets the name of the series in charts, the key is left untouched.
+// An empty alias resets it to the key.
+func (ts *TimeSeries) SetAlias(alias string) {
+	ts.alias = alias
+}
+
+// AliasByNode sets the alias to dot separated nodes of the key joined by
+// dots, like graphite's aliasByNode. Negative nodes count from the end and
+// transforms wrapping the key are ignored.
+func (ts *TimeSeries) AliasByNode(nodes ...int) error {
+	if len(nodes) == 0 {
+		return fmt.Errorf("aliasing needs at least one node")
+	}
+	alias, err := keyNodes(ts.key, nodes)
+	if err != nil {
+		return err
+	}
+	ts.alias = alias
+	return nil
+}
+
+// AliasSub replaces the matches of pattern in the alias by replace, which can
+// refer to submatches as $1, like graphite's aliasSub.
+func (ts *TimeSeries) AliasSub(pattern, replace string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	ts.alias = re.ReplaceAllString(ts.Alias(), replace)
+	return nil
+}
+
+// AliasTemplate sets the alias from a template where {name} is replaced by
+// the label name, or by "" if the series doesn't have it, and {N} by the
+// node N of the key, like "{dc} {-1}".
+func (ts *TimeSeries) AliasTemplate(template string) error {
+	alias, err := ts.expand(template)
+	if err != nil {
+		return err
+	}
+	ts.alias = alias
+	return nil
+}
+
+func (ts *TimeSeries) expand(template string) (string, error) {
+	s := ""
+	for {
+		open := strings.Index(template, "{")
+		if open == -1 {
+			return s + template, nil
+		}
+		end := strings.Index(template[open:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("template '%s' has an unclosed '{'", template)
+		}
+		field := template[open+1 : open+end]
+		s += template[:open]
+		template = template[open+end+1:]
+
+		if node, err := strconv.Atoi(field); err == nil {
+			v, err := keyNodes(ts.key, []int{node})
+			if err != nil {
+				return "", err
+			}
+			s += v
+		} else {
+			s += ts.labels[field]
+		}
+	}
+}
+
+// Aliases returns the aliases of the series, see TimeSeries.Alias.
+func (tss TimeSeriesSlice) Aliases() []string {
+	aliases := make([]string, len(tss))
+	for i, _ := range tss {
+		aliases[i] = tss[i].Alias()
+	}
+	return aliases
+}
+
+// SetAlias sets the same alias to all the series, like graphite's alias.
+func (tss TimeSeriesSlice) SetAlias(alias string) {
+	for i, _ := range tss {
+		tss[i].alias = alias
+	}
+}
+
+func (tss TimeSeriesSlice) AliasByNode(nodes ...int) error {
+	for i, _ := range tss {
+		if err := tss[i].AliasByNode(nodes...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tss TimeSeriesSlice) AliasSub(pattern, replace string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	for i, _ := range tss {
+		tss[i].alias = re.ReplaceAllString(tss[i].Alias(), replace)
+	}
+	return nil
+}
+
+func (tss TimeSeriesSlice) AliasTemplate(template string) error {
+	for i, _ := range tss {
+		if err := tss[i].AliasTemplate(template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"testing"
+	"time"
+)
+
+type double struct{}
+
+func (d double) Name() string {
+	return "Double"
+}
+
+func (d double) Transform(v float64) float64 {
+	return 2 * v
+}
+
+func TestAlias(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+
+	ts0, err := NewTimeSeriesOfData("servers.dc1-web1.cpu.user", start, time.Minute, []float64{1, 2})
+	checkErr(t, err)
+	ts0.SetLabels(Labels{"dc": "dc1", "host": "web1"})
+	checkKey(t, ts0.Alias(), "servers.dc1-web1.cpu.user")
+
+	ts0.SetAlias("cpu")
+	checkKey(t, ts0.Alias(), "cpu")
+	checkKey(t, ts0.Key(), "servers.dc1-web1.cpu.user")
+	checkKey(t, ts0.Copy().Alias(), "cpu")
+	checkKey(t, ts0.View(start, start.Add(time.Minute)).Alias(), "cpu")
+	checkKey(t, ts0.Transform(double{}).Alias(), "Double(servers.dc1-web1.cpu.user)")
+	checkKey(t, ts0.MulScalar(2).Alias(), "Mul(servers.dc1-web1.cpu.user,2)")
+	checkKey(t, ts0.TimeShift(time.Hour).Alias(), "timeShift(1h)(servers.dc1-web1.cpu.user)")
+
+	checkErr(t, ts0.AliasByNode(1, -1))
+	checkKey(t, ts0.Alias(), "dc1-web1.user")
+
+	checkErr(t, ts0.AliasSub(`^(\w+)-(\w+)`, "$2@$1"))
+	checkKey(t, ts0.Alias(), "web1@dc1.user")
+
+	checkErr(t, ts0.AliasTemplate("{host} in {dc} {missing}{-1}"))
+	checkKey(t, ts0.Alias(), "web1 in dc1 user")
+
+	ts0.SetAlias("")
+	checkKey(t, ts0.Alias(), "servers.dc1-web1.cpu.user")
+
+	if err := ts0.AliasByNode(5); err == nil {
+		t.Errorf("FAIL(error): key has no node 5")
+	}
+	if err := ts0.AliasTemplate("{host"); err == nil {
+		t.Errorf("FAIL(error): template is unclosed")
+	}
+
+	ts1 := ts0.Transform(double{})
+	tss := TimeSeriesSlice{*ts0, *ts1}
+	checkErr(t, tss.AliasByNode(1))
+	checkKey(t, string(tss.JSTag()), "dc1-web1,dc1-web1")
+	checkErr(t, tss.AliasSub(`dc1-`, ""))
+	checkKey(t, string(tss.JSTag()), "web1,web1")
+	checkKey(t, tss.Key(), "servers.dc1-web1.cpu.user,Double(servers.dc1-web1.cpu.user)")
+	checkErr(t, tss.AliasTemplate("{2}"))
+	tss.SetAlias("all")
+	checkKey(t, string(tss.JSTag()), "all,all")
+}
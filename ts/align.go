@@ -0,0 +1,126 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// average is the default consolidation, as in graphite.
+type average struct{}
+
+func (a average) Name() string {
+	return "Average"
+}
+
+func (a average) TransformSlice(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+// AlignTo resamples the series on [start, end) with the given step. When
+// downsampling, the non NaN values in each step are consolidated, average is
+// used when consolidation is nil. When upsampling, values are linearly
+// interpolated between two known points and held otherwise.
+func (ts *TimeSeries) AlignTo(start, end time.Time, step time.Duration, consolidation TranformSlice) (*TimeSeries, error) {
+	if consolidation == nil {
+		consolidation = average{}
+	}
+	res, err := NewTimeSeries(ts.key, start, end, step, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+	res.filler = ts.filler
+	res.labels = ts.labels.Copy()
+
+	if step < ts.step {
+		ts.interpolateInto(res, InterpolateLinear)
+	} else {
+		ts.consolidateInto(res, consolidation)
+	}
+	return res, nil
+}
+
+func (ts *TimeSeries) consolidateInto(res *TimeSeries, consolidation TranformSlice) {
+	buckets := make([][]float64, len(res.data))
+	it := ts.IteratorTimeValue()
+	for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+		if math.IsNaN(v) || t.Before(res.start) {
+			continue
+		}
+		i := int(t.Sub(res.start) / res.step)
+		if i >= len(buckets) {
+			break
+		}
+		buckets[i] = append(buckets[i], v)
+	}
+	for i, bucket := range buckets {
+		if len(bucket) > 0 {
+			res.data[i] = consolidation.TransformSlice(bucket)
+		}
+	}
+}
+
+func (ts *TimeSeries) interpolateInto(res *TimeSeries, interpolation Interpolation) {
+	cursor := res.start
+	for i, _ := range res.data {
+		res.data[i], _ = ts.GetInterpolated(cursor, interpolation)
+		cursor = cursor.Add(res.step)
+	}
+}
+
+// Align brings all the series to the largest of their steps, over the range
+// of the slice with the start truncated and the end rounded up to the step.
+// The finer series are consolidated, see AlignTo for the consolidation. Use
+// AlignTo to upsample to a smaller step.
+func (tss TimeSeriesSlice) Align(consolidation TranformSlice) (TimeSeriesSlice, error) {
+	if len(tss) == 0 {
+		return tss, nil
+	}
+	step := tss[0].step
+	for i := 1; i < len(tss); i++ {
+		if tss[i].step > step {
+			step = tss[i].step
+		}
+	}
+	start := tss.Start().Truncate(step)
+	end := tss.End()
+	if !end.Truncate(step).Equal(end) {
+		end = end.Truncate(step).Add(step)
+	}
+	return tss.AlignTo(start, end, step, consolidation)
+}
+
+// AlignTo brings all the series to the given range and step.
+func (tss TimeSeriesSlice) AlignTo(start, end time.Time, step time.Duration, consolidation TranformSlice) (TimeSeriesSlice, error) {
+	res := make(TimeSeriesSlice, 0, len(tss))
+	for i, _ := range tss {
+		ts, err := tss[i].AlignTo(start, end, step, consolidation)
+		if err != nil {
+			return nil, fmt.Errorf("aligning '%s': %s", tss[i].key, err)
+		}
+		res = append(res, *ts)
+	}
+	return res, nil
+}
+
+// aligned returns the slice itself if all the steps are equal, or the slice
+// aligned with the default consolidation.
+func (tss TimeSeriesSlice) aligned() (TimeSeriesSlice, time.Duration, error) {
+	if step, ok := tss.checkEqualStep(); ok {
+		return tss, step, nil
+	}
+	if len(tss) == 0 {
+		return tss, 0, fmt.Errorf("empty time series slice has no step")
+	}
+	aligned, err := tss.Align(nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	return aligned, aligned[0].step, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type sum struct{}
+
+func (s sum) Name() string {
+	return "Sum"
+}
+
+func (s sum) TransformSlice(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
+
+func TestAlign(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+
+	a, err := NewTimeSeriesOfData("a", start, time.Minute, []float64{1, 2, 3, NaN, 5, 6})
+	checkErr(t, err)
+	b, err := NewTimeSeriesOfData("b", start, 2*time.Minute, []float64{10, 20, 30})
+	checkErr(t, err)
+	c, err := NewTimeSeriesOfData("c", start.Add(3*time.Minute), 3*time.Minute, []float64{100})
+	checkErr(t, err)
+
+	up, err := b.AlignTo(start, start.Add(7*time.Minute), time.Minute, nil)
+	checkErr(t, err)
+	checkTimeSeries(t, up, &TimeSeries{
+		key: "b", start: start, step: time.Minute, data: []float64{10, 15, 20, 25, 30, 30, NaN},
+	})
+
+	aligned, err := TimeSeriesSlice{*a, *b, *c}.Align(nil)
+	checkErr(t, err)
+	exp := TimeSeriesSlice{
+		{key: "a", start: start, step: 3 * time.Minute, data: []float64{2, 5.5}},
+		{key: "b", start: start, step: 3 * time.Minute, data: []float64{15, 30}},
+		{key: "c", start: start, step: 3 * time.Minute, data: []float64{NaN, 100}},
+	}
+	for i, _ := range exp {
+		fmt.Printf("%s\n%s\n\n", aligned[i], exp[i])
+		checkTimeSeries(t, &aligned[i], &exp[i])
+	}
+
+	// steps that aren't multiples of each other keep all their values.
+	x, err := NewTimeSeriesOfData("x", start, 7*time.Second, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	checkErr(t, err)
+	y, err := NewTimeSeriesOfData("y", start, time.Minute, []float64{1, 2})
+	checkErr(t, err)
+	aligned, err = TimeSeriesSlice{*x, *y}.Align(nil)
+	checkErr(t, err)
+	exp = TimeSeriesSlice{
+		{key: "x", start: start, step: time.Minute, data: []float64{5, 10}},
+		{key: "y", start: start, step: time.Minute, data: []float64{1, 2}},
+	}
+	for i, _ := range exp {
+		checkTimeSeries(t, &aligned[i], &exp[i])
+	}
+
+	summed := TimeSeriesSlice{*a, *b}.TransformSlice(sum{})
+	checkTimeSeries(t, summed, &TimeSeries{
+		key: "Sum(a,b)", start: start, step: 2 * time.Minute, data: []float64{11.5, 23, 35.5},
+	})
+
+	// charting mixed steps or nothing doesn't panic.
+	TimeSeriesSlice{*a, *c}.C3Data()
+	TimeSeriesSlice{*a, *c}.C3Time()
+	if js := (TimeSeriesSlice{}).C3Data(); js != "" {
+		t.Errorf("FAIL(c3): got '%s' for an empty slice", js)
+	}
+	if js := (TimeSeriesSlice{}).C3Time(); js != "" {
+		t.Errorf("FAIL(c3): got '%s' for an empty slice", js)
+	}
+}
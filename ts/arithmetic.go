@@ -0,0 +1,166 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Join selects the time range of the result of an operation on two series.
+type Join int
+
+const (
+	// OuterJoin covers the union of both series.
+	OuterJoin Join = iota
+	// InnerJoin covers the intersection of both series.
+	InnerJoin
+	// LeftJoin covers the first series.
+	LeftJoin
+)
+
+// Missing selects the result when one side of an operation is outside of its
+// series or NaN.
+type Missing int
+
+const (
+	// MissingNaN results in NaN.
+	MissingNaN Missing = iota
+	// MissingFill replaces the missing side by the Fill value.
+	MissingFill
+	// MissingOther results in the value of the other side.
+	MissingOther
+)
+
+// JoinPolicy is how two series are combined, the zero value is an outer join
+// where missing values result in NaN.
+type JoinPolicy struct {
+	Join    Join
+	Missing Missing
+	Fill    float64
+}
+
+func (ts *TimeSeries) Add(other *TimeSeries, policy JoinPolicy) (*TimeSeries, error) {
+	return ts.combine("Add", other, policy, func(a, b float64) float64 { return a + b })
+}
+
+func (ts *TimeSeries) Sub(other *TimeSeries, policy JoinPolicy) (*TimeSeries, error) {
+	return ts.combine("Sub", other, policy, func(a, b float64) float64 { return a - b })
+}
+
+func (ts *TimeSeries) Mul(other *TimeSeries, policy JoinPolicy) (*TimeSeries, error) {
+	return ts.combine("Mul", other, policy, func(a, b float64) float64 { return a * b })
+}
+
+// Div results in NaN when dividing by 0.
+func (ts *TimeSeries) Div(other *TimeSeries, policy JoinPolicy) (*TimeSeries, error) {
+	return ts.combine("Div", other, policy, div)
+}
+
+func (ts *TimeSeries) AddScalar(v float64) *TimeSeries {
+	return ts.scalar("Add", v, func(a, b float64) float64 { return a + b })
+}
+
+func (ts *TimeSeries) SubScalar(v float64) *TimeSeries {
+	return ts.scalar("Sub", v, func(a, b float64) float64 { return a - b })
+}
+
+func (ts *TimeSeries) MulScalar(v float64) *TimeSeries {
+	return ts.scalar("Mul", v, func(a, b float64) float64 { return a * b })
+}
+
+// DivScalar results in NaN when dividing by 0.
+func (ts *TimeSeries) DivScalar(v float64) *TimeSeries {
+	return ts.scalar("Div", v, div)
+}
+
+func div(a, b float64) float64 {
+	if b == 0 {
+		return math.NaN()
+	}
+	return a / b
+}
+
+func (ts *TimeSeries) scalar(name string, v float64, op func(float64, float64) float64) *TimeSeries {
+	res := ts.Copy()
+	res.key = fmt.Sprintf("%s(%s,%g)", name, ts.key, v)
+	res.alias = ""
+	for i, d := range res.data {
+		res.data[i] = op(d, v)
+	}
+	return res
+}
+
+func (ts *TimeSeries) combine(name string, other *TimeSeries, policy JoinPolicy, op func(float64, float64) float64) (*TimeSeries, error) {
+	if other == nil {
+		return nil, fmt.Errorf("%s: other series can't be nil", name)
+	}
+	if !ts.IsEqualStep(other) {
+		return nil, fmt.Errorf("%s: step sizes don't match, %v != %v", name, ts.step, other.step)
+	}
+
+	var start, end time.Time
+	switch policy.Join {
+	case OuterJoin:
+		start, end = ts.start, ts.End()
+		if other.start.Before(start) {
+			start = other.start
+		}
+		if other.End().After(end) {
+			end = other.End()
+		}
+	case InnerJoin:
+		start, end = ts.start, ts.End()
+		if other.start.After(start) {
+			start = other.start
+		}
+		if other.End().Before(end) {
+			end = other.End()
+		}
+		if end.Before(start) {
+			end = start
+		}
+	case LeftJoin:
+		start, end = ts.start, ts.End()
+	default:
+		return nil, fmt.Errorf("%s: unknown join %d", name, policy.Join)
+	}
+
+	res, err := NewTimeSeries(fmt.Sprintf("%s(%s,%s)", name, ts.key, other.key), start, end, ts.step, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := start
+	for i, _ := range res.data {
+		a, okA := ts.GetAt(cursor)
+		b, okB := other.GetAt(cursor)
+		cursor = cursor.Add(ts.step)
+
+		okA = okA && !math.IsNaN(a)
+		okB = okB && !math.IsNaN(b)
+		switch {
+		case okA && okB:
+		case !okA && !okB:
+			continue
+		case policy.Missing == MissingFill:
+			if !okA {
+				a = policy.Fill
+			} else {
+				b = policy.Fill
+			}
+		case policy.Missing == MissingOther:
+			if okA {
+				res.data[i] = a
+			} else {
+				res.data[i] = b
+			}
+			continue
+		default:
+			continue
+		}
+		res.data[i] = op(a, b)
+	}
+	return res, nil
+}
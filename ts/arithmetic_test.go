@@ -0,0 +1,77 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestArithmetic(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	a, err := NewTimeSeriesOfData("a", start, step, []float64{1, 2, 3, 4})
+	checkErr(t, err)
+	b, err := NewTimeSeriesOfData("b", start.Add(step), step, []float64{10, NaN, 30, 40})
+	checkErr(t, err)
+	c, err := NewTimeSeriesOfData("c", start, time.Hour, []float64{1})
+	checkErr(t, err)
+
+	combine := func(res *TimeSeries, err error) *TimeSeries {
+		checkErr(t, err)
+		return res
+	}
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: combine(a.Add(b, JoinPolicy{})),
+			Exp: &TimeSeries{key: "Add(a,b)", start: start, step: step, data: []float64{NaN, 12, NaN, 34, NaN}},
+		},
+		{
+			Got: combine(a.Add(b, JoinPolicy{Join: InnerJoin})),
+			Exp: &TimeSeries{key: "Add(a,b)", start: start.Add(step), step: step, data: []float64{12, NaN, 34}},
+		},
+		{
+			Got: combine(a.Add(b, JoinPolicy{Join: LeftJoin, Missing: MissingFill, Fill: 0})),
+			Exp: &TimeSeries{key: "Add(a,b)", start: start, step: step, data: []float64{1, 12, 3, 34}},
+		},
+		{
+			Got: combine(a.Sub(b, JoinPolicy{Missing: MissingOther})),
+			Exp: &TimeSeries{key: "Sub(a,b)", start: start, step: step, data: []float64{1, -8, 3, -26, 40}},
+		},
+		{
+			Got: combine(b.Mul(a, JoinPolicy{Join: LeftJoin})),
+			Exp: &TimeSeries{key: "Mul(b,a)", start: start.Add(step), step: step, data: []float64{20, NaN, 120, NaN}},
+		},
+		{
+			Got: combine(a.Div(b, JoinPolicy{Join: InnerJoin, Missing: MissingFill, Fill: 0})),
+			Exp: &TimeSeries{key: "Div(a,b)", start: start.Add(step), step: step, data: []float64{0.2, NaN, 4.0 / 30}},
+		},
+		{
+			Got: a.MulScalar(2),
+			Exp: &TimeSeries{key: "Mul(a,2)", start: start, step: step, data: []float64{2, 4, 6, 8}},
+		},
+		{
+			Got: a.DivScalar(0),
+			Exp: &TimeSeries{key: "Div(a,0)", start: start, step: step, data: []float64{NaN, NaN, NaN, NaN}},
+		},
+		{
+			Got: b.SubScalar(0.5).AddScalar(1),
+			Exp: &TimeSeries{key: "Add(Sub(b,0.5),1)", start: start.Add(step), step: step, data: []float64{10.5, NaN, 30.5, 40.5}},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+
+	if _, err := a.Add(c, JoinPolicy{}); err == nil {
+		t.Errorf("FAIL(error): different steps should fail")
+	}
+}
@@ -51,7 +51,7 @@ func (carbon *Carbon) Send() {
 	for {
 		buffer := <-carbon.feed
 		if _, err := io.Copy(carbon.conn, &buffer); err != nil {
-			log.Printf("carbon.Send.error:", err)
+			log.Printf("carbon.Send.error: %s", err)
 		}
 	}
 }
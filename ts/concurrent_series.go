@@ -0,0 +1,114 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"sync"
+	"time"
+)
+
+// NewConcurrentSeries wraps a copy of ts to be shared between goroutines.
+func NewConcurrentSeries(ts *TimeSeries) *ConcurrentSeries {
+	return &ConcurrentSeries{
+		series: ts.Copy(),
+	}
+}
+
+// ConcurrentSeries is a TimeSeries safe for concurrent use. Writers modify it
+// in place while readers work on snapshots that later writes don't affect.
+type ConcurrentSeries struct {
+	lock   sync.RWMutex
+	series *TimeSeries
+}
+
+func (cs *ConcurrentSeries) Key() string {
+	cs.lock.RLock()
+	defer cs.lock.RUnlock()
+	return cs.series.key
+}
+func (cs *ConcurrentSeries) SetKey(key string) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.series.key = key
+}
+func (cs *ConcurrentSeries) Start() time.Time {
+	cs.lock.RLock()
+	defer cs.lock.RUnlock()
+	return cs.series.start
+}
+func (cs *ConcurrentSeries) End() time.Time {
+	cs.lock.RLock()
+	defer cs.lock.RUnlock()
+	return cs.series.End()
+}
+func (cs *ConcurrentSeries) Step() time.Duration {
+	cs.lock.RLock()
+	defer cs.lock.RUnlock()
+	return cs.series.step
+}
+
+// Snapshot returns a copy of the series at this instant.
+func (cs *ConcurrentSeries) Snapshot() *TimeSeries {
+	cs.lock.RLock()
+	defer cs.lock.RUnlock()
+	return cs.series.Copy()
+}
+
+func (cs *ConcurrentSeries) GetAt(t time.Time) (float64, bool) {
+	cs.lock.RLock()
+	defer cs.lock.RUnlock()
+	return cs.series.GetAt(t)
+}
+
+func (cs *ConcurrentSeries) SetAt(t time.Time, value float64) bool {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	return cs.series.SetAt(t, value)
+}
+
+func (cs *ConcurrentSeries) ExtendTo(t time.Time) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.series.ExtendTo(t)
+}
+func (cs *ConcurrentSeries) ExtendBy(d time.Duration) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.series.ExtendBy(d)
+}
+func (cs *ConcurrentSeries) ExtendWith(data ...float64) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.series.ExtendWith(data...)
+}
+
+// Record sets the value at t, extending the series first if t is past its
+// end, as a single atomic operation.
+func (cs *ConcurrentSeries) Record(t time.Time, value float64) bool {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.series.ExtendTo(t)
+	return cs.series.SetAt(t, value)
+}
+
+// Update applies f to the series while holding the write lock, f must not
+// keep a reference to the series.
+func (cs *ConcurrentSeries) Update(f func(*TimeSeries)) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	f(cs.series)
+}
+
+// Iterator iterates over a snapshot of the series.
+func (cs *ConcurrentSeries) Iterator() *Iterator {
+	return cs.Snapshot().Iterator()
+}
+
+// IteratorTimeValue iterates over a snapshot of the series.
+func (cs *ConcurrentSeries) IteratorTimeValue() *IteratorTimeValue {
+	return cs.Snapshot().IteratorTimeValue()
+}
+
+func (cs *ConcurrentSeries) String() string {
+	return cs.Snapshot().String()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Second
+	writers, points := 4, 100
+
+	ts, err := NewTimeSeriesOfData("concurrent", start, step, []float64{})
+	checkErr(t, err)
+	cs := NewConcurrentSeries(ts)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < points; i += writers {
+				cs.Record(start.Add(time.Duration(i)*step), float64(i))
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			cs.Snapshot()
+			it := cs.IteratorTimeValue()
+			for _, _, ok := it.Next(); ok; _, _, ok = it.Next() {
+			}
+			cs.GetAt(start)
+			cs.End()
+		}
+	}()
+
+	wg.Wait()
+	<-done
+
+	got := cs.Snapshot()
+	if len(got.Data()) != points {
+		t.Fatalf("FAIL(length): got: '%d', expected '%d'", len(got.Data()), points)
+	}
+	for i, v := range got.Data() {
+		if v != float64(i) {
+			t.Errorf("FAIL(data): at index '%d' got '%f'", i, v)
+		}
+	}
+	if !ts.End().Equal(start) {
+		t.Errorf("FAIL(end): wrapped series can't be modified")
+	}
+
+	// a snapshot is never changed by later writes, in place or extending.
+	first := cs.Snapshot()
+	cs.Record(start, -1)
+	cs.Record(start.Add(time.Duration(points)*step), float64(points))
+	second := cs.Snapshot()
+
+	checkData(t, first.Data(), got.Data())
+	if end := start.Add(time.Duration(points) * step); !first.End().Equal(end) {
+		t.Errorf("FAIL(snapshot): got end '%s', expected '%s'", first.End(), end)
+	}
+	if v, _ := second.GetAt(start); v != -1 {
+		t.Errorf("FAIL(snapshot): got '%f' at start, expected '-1'", v)
+	}
+	if end := start.Add(time.Duration(points+1) * step); !second.End().Equal(end) {
+		t.Errorf("FAIL(snapshot): got end '%s', expected '%s'", second.End(), end)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+type CSVLayout int
+
+const (
+	// CSVWide has a time column followed by a column per series key, with a
+	// row per step over the range of the slice.
+	CSVWide CSVLayout = iota
+	// CSVLong has the key,time,value columns and a row per point.
+	CSVLong
+)
+
+// CSVUnixTime is the time format writing times as unix seconds.
+const CSVUnixTime = "unix"
+
+type CSVOptions struct {
+	Layout CSVLayout
+	// TimeFormat is a time layout or CSVUnixTime, RFC3339 if empty.
+	TimeFormat string
+	// NaN is written for NaN and missing values, and read as NaN along with
+	// "NaN".
+	NaN string
+	// Step is the step of the series with a single point or none, it can't
+	// be read from their times. ReadCSV fails on such series if it isn't set.
+	Step time.Duration
+}
+
+func (opts *CSVOptions) formatTime(t time.Time) string {
+	switch opts.TimeFormat {
+	case "":
+		return t.Format(time.RFC3339)
+	case CSVUnixTime:
+		return strconv.FormatInt(t.Unix(), 10)
+	default:
+		return t.Format(opts.TimeFormat)
+	}
+}
+
+func (opts *CSVOptions) parseTime(s string) (time.Time, error) {
+	switch opts.TimeFormat {
+	case "":
+		return time.Parse(time.RFC3339, s)
+	case CSVUnixTime:
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(sec, 0).UTC(), nil
+	default:
+		return time.Parse(opts.TimeFormat, s)
+	}
+}
+
+func (opts *CSVOptions) formatValue(v float64) string {
+	if math.IsNaN(v) {
+		return opts.NaN
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (opts *CSVOptions) parseValue(s string) (float64, error) {
+	if s == opts.NaN || s == "NaN" {
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// WriteCSV writes the slice with a header row. The wide layout requires the
+// series to have the same step.
+func (tss TimeSeriesSlice) WriteCSV(w io.Writer, opts CSVOptions) error {
+	cw := csv.NewWriter(w)
+	switch opts.Layout {
+	case CSVWide:
+		if err := tss.writeCSVWide(cw, &opts); err != nil {
+			return err
+		}
+	case CSVLong:
+		if err := tss.writeCSVLong(cw, &opts); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown csv layout %d", opts.Layout)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (tss TimeSeriesSlice) writeCSVWide(cw *csv.Writer, opts *CSVOptions) error {
+	record := make([]string, len(tss)+1)
+	record[0] = "time"
+	for i, _ := range tss {
+		record[i+1] = tss[i].key
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	if len(tss) == 0 {
+		return nil
+	}
+
+	step, ok := tss.checkEqualStep()
+	if !ok {
+		return fmt.Errorf("step sizes don't match")
+	}
+	for cursor, end := tss.Start(), tss.End(); cursor.Before(end); cursor = cursor.Add(step) {
+		record[0] = opts.formatTime(cursor)
+		for i, _ := range tss {
+			v, _ := tss[i].GetAt(cursor)
+			record[i+1] = opts.formatValue(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tss TimeSeriesSlice) writeCSVLong(cw *csv.Writer, opts *CSVOptions) error {
+	if err := cw.Write([]string{"key", "time", "value"}); err != nil {
+		return err
+	}
+	record := make([]string, 3)
+	for i, _ := range tss {
+		record[0] = tss[i].key
+		it := tss[i].IteratorTimeValue()
+		for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+			record[1] = opts.formatTime(t)
+			record[2] = opts.formatValue(v)
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReadCSV reads a slice written by WriteCSV with the same options. The start
+// of a series is its first time and its step the smallest distance between
+// two of its times, or the Step of the options for a single point. A series
+// without points, like those of a wide header without rows, has a zero start
+// and the Step of the options. Times not falling on a step are an error.
+func ReadCSV(r io.Reader, opts CSVOptions) (TimeSeriesSlice, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	points := make(map[string][]Point)
+	add := func(key string, t time.Time, v float64) {
+		if _, ok := points[key]; !ok {
+			keys = append(keys, key)
+		}
+		points[key] = append(points[key], Point{t, v})
+	}
+
+	switch opts.Layout {
+	case CSVWide:
+		if len(header) < 1 {
+			return nil, fmt.Errorf("csv header has no time column")
+		}
+		for _, key := range header[1:] {
+			if _, ok := points[key]; ok {
+				return nil, fmt.Errorf("csv header has key '%s' twice", key)
+			}
+			keys = append(keys, key)
+			points[key] = []Point{}
+		}
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			t, err := opts.parseTime(record[0])
+			if err != nil {
+				return nil, err
+			}
+			for i, key := range header[1:] {
+				v, err := opts.parseValue(record[i+1])
+				if err != nil {
+					return nil, err
+				}
+				points[key] = append(points[key], Point{t, v})
+			}
+		}
+
+	case CSVLong:
+		if len(header) != 3 {
+			return nil, fmt.Errorf("csv header '%v' isn't key,time,value", header)
+		}
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			t, err := opts.parseTime(record[1])
+			if err != nil {
+				return nil, err
+			}
+			v, err := opts.parseValue(record[2])
+			if err != nil {
+				return nil, err
+			}
+			add(record[0], t, v)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown csv layout %d", opts.Layout)
+	}
+
+	tss := make(TimeSeriesSlice, 0, len(keys))
+	for _, key := range keys {
+		ts, err := timeSeriesOfPoints(key, points[key], opts.Step)
+		if err != nil {
+			return nil, err
+		}
+		tss = append(tss, *ts)
+	}
+	return tss, nil
+}
+
+func timeSeriesOfPoints(key string, points []Point, single time.Duration) (*TimeSeries, error) {
+	sort.Stable(byTime(points))
+
+	var step time.Duration
+	for i := 1; i < len(points); i++ {
+		d := points[i].Time.Sub(points[i-1].Time)
+		if d == 0 {
+			return nil, fmt.Errorf("series '%s' has two values at %v", key, points[i].Time)
+		}
+		if step == 0 || d < step {
+			step = d
+		}
+	}
+	if step == 0 && len(points) <= 1 {
+		step = single
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("step of series '%s' can't be determined", key)
+	}
+
+	if len(points) == 0 {
+		return &TimeSeries{
+			key:    key,
+			step:   step,
+			data:   []float64{},
+			filler: math.NaN(),
+			shared: new(atomic.Bool),
+		}, nil
+	}
+
+	start := points[0].Time
+	end := points[len(points)-1].Time.Add(step)
+	ts, err := NewTimeSeries(key, start, end, step, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		if p.Time.Sub(start)%step != 0 {
+			return nil, fmt.Errorf("series '%s' time %v isn't a multiple of step %v", key, p.Time, step)
+		}
+		ts.SetAt(p.Time, p.Value)
+	}
+	return ts, nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesSliceCSV(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 15, 17, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("a.b", start, step, []float64{1, NaN, 1.5})
+	checkErr(t, err)
+	ts1, err := NewTimeSeriesOfData("c,d", start.Add(step), step, []float64{2, 3, 4})
+	checkErr(t, err)
+	tss := TimeSeriesSlice{*ts0, *ts1}
+
+	tests := []struct {
+		Opts CSVOptions
+		Exp  string
+		Got  TimeSeriesSlice
+	}{
+		{
+			Opts: CSVOptions{Layout: CSVWide, TimeFormat: CSVUnixTime, NaN: "-"},
+			Exp: `time,a.b,"c,d"
+1452877200,1,-
+1452877260,-,2
+1452877320,1.5,3
+1452877380,-,4
+`,
+			// the wide layout aligns all the series on the slice range.
+			Got: TimeSeriesSlice{
+				{key: "a.b", start: start, step: step, data: []float64{1, NaN, 1.5, NaN}},
+				{key: "c,d", start: start, step: step, data: []float64{NaN, 2, 3, 4}},
+			},
+		},
+		{
+			Opts: CSVOptions{Layout: CSVLong},
+			Exp: `key,time,value
+a.b,2016-01-15T17:00:00Z,1
+a.b,2016-01-15T17:01:00Z,
+a.b,2016-01-15T17:02:00Z,1.5
+"c,d",2016-01-15T17:01:00Z,2
+"c,d",2016-01-15T17:02:00Z,3
+"c,d",2016-01-15T17:03:00Z,4
+`,
+			Got: tss,
+		},
+	}
+
+	for _, test := range tests {
+		b := &bytes.Buffer{}
+		checkErr(t, tss.WriteCSV(b, test.Opts))
+		if b.String() != test.Exp {
+			t.Errorf("FAIL(csv): got:\n%s\nexpected:\n%s", b, test.Exp)
+		}
+
+		got, err := ReadCSV(strings.NewReader(b.String()), test.Opts)
+		checkErr(t, err)
+		if len(got) != len(test.Got) {
+			t.Errorf("FAIL(length): got: '%d', expected '%d'", len(got), len(test.Got))
+			continue
+		}
+		for i, _ := range got {
+			checkTimeSeries(t, &got[i], &test.Got[i])
+		}
+	}
+
+	// the step of a single point or of none can't be read from the file, it
+	// comes from the options when reading.
+	single, err := NewTimeSeriesOfData("single", start, step, []float64{1})
+	checkErr(t, err)
+	empty := &TimeSeries{key: "empty", step: step, data: []float64{}}
+	roundTrips := []struct {
+		Layout CSVLayout
+		Tss    TimeSeriesSlice
+	}{
+		{Layout: CSVWide, Tss: TimeSeriesSlice{*single}},
+		{Layout: CSVLong, Tss: TimeSeriesSlice{*single}},
+		{Layout: CSVWide, Tss: TimeSeriesSlice{*empty}},
+	}
+
+	for _, test := range roundTrips {
+		b := &bytes.Buffer{}
+		checkErr(t, test.Tss.WriteCSV(b, CSVOptions{Layout: test.Layout}))
+
+		if _, err := ReadCSV(strings.NewReader(b.String()), CSVOptions{Layout: test.Layout}); err == nil {
+			t.Errorf("FAIL(csv): reading '%s' without a step in the options should fail", test.Tss[0].key)
+		}
+		got, err := ReadCSV(strings.NewReader(b.String()), CSVOptions{Layout: test.Layout, Step: step})
+		checkErr(t, err)
+		if len(got) != len(test.Tss) {
+			t.Errorf("FAIL(length): got: '%d', expected '%d'", len(got), len(test.Tss))
+			continue
+		}
+		for i, _ := range got {
+			checkTimeSeries(t, &got[i], &test.Tss[i])
+		}
+	}
+
+	// two series with the same key write fine but can't be told apart when
+	// read back.
+	dup := TimeSeriesSlice{*ts0, *ts0}
+	for _, layout := range []CSVLayout{CSVWide, CSVLong} {
+		b := &bytes.Buffer{}
+		checkErr(t, dup.WriteCSV(b, CSVOptions{Layout: layout}))
+		if _, err := ReadCSV(strings.NewReader(b.String()), CSVOptions{Layout: layout}); err == nil {
+			t.Errorf("FAIL(csv): reading duplicate keys should fail")
+		}
+	}
+
+	_, err = ReadCSV(strings.NewReader("key,time,value\nx,60,1\nx,120,1\nx,150,1\n"),
+		CSVOptions{Layout: CSVLong, TimeFormat: CSVUnixTime})
+	checkErr(t, err)
+	_, err = ReadCSV(strings.NewReader("key,time,value\nx,60,1\nx,120,1\nx,210,1\n"),
+		CSVOptions{Layout: CSVLong, TimeFormat: CSVUnixTime})
+	if err == nil {
+		t.Errorf("FAIL(csv): times off the step should fail")
+	}
+}
@@ -0,0 +1,424 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Binary encoding of time series.
+//
+// The header of a series holds its key, alias, start, step, filler and labels.
+// Version 1 had no alias and is still read. The
+// start and step are stored as the difference with the previous series of a
+// slice, since fetched slices usually share them. The values are compressed
+// with the XOR encoding of facebook's Gorilla paper, the bits of each value
+// are XOR'ed with the previous one and only the meaningful bits are kept.
+// Values, NaN included, are kept bit for bit. Start times are decoded in UTC.
+
+const encodingVersion = 2
+
+var errShortBuffer = errors.New("binary time series data is truncated")
+
+func (ts *TimeSeries) MarshalBinary() ([]byte, error) {
+	e := &encoder{}
+	e.buf.WriteByte(encodingVersion)
+	e.series(ts)
+	return e.buf.Bytes(), nil
+}
+
+func (ts *TimeSeries) UnmarshalBinary(data []byte) error {
+	d, err := newDecoder(data)
+	if err != nil {
+		return err
+	}
+	series, err := d.series()
+	if err != nil {
+		return err
+	}
+	if d.buf.Len() != 0 {
+		return fmt.Errorf("%d bytes left after time series", d.buf.Len())
+	}
+	*ts = *series
+	return nil
+}
+
+func (tss TimeSeriesSlice) MarshalBinary() ([]byte, error) {
+	e := &encoder{}
+	e.buf.WriteByte(encodingVersion)
+	e.uvarint(uint64(len(tss)))
+	for i, _ := range tss {
+		e.series(&tss[i])
+	}
+	return e.buf.Bytes(), nil
+}
+
+func (tss *TimeSeriesSlice) UnmarshalBinary(data []byte) error {
+	d, err := newDecoder(data)
+	if err != nil {
+		return err
+	}
+	n, err := binary.ReadUvarint(d.buf)
+	if err != nil {
+		return errShortBuffer
+	}
+	if n > uint64(d.buf.Len()) {
+		return fmt.Errorf("time series count %d is larger than the data", n)
+	}
+	res := make(TimeSeriesSlice, 0, n)
+	for i := uint64(0); i < n; i++ {
+		series, err := d.series()
+		if err != nil {
+			return err
+		}
+		res = append(res, *series)
+	}
+	if d.buf.Len() != 0 {
+		return fmt.Errorf("%d bytes left after time series slice", d.buf.Len())
+	}
+	*tss = res
+	return nil
+}
+
+type encoder struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+
+	start int64
+	step  int64
+}
+
+func (e *encoder) uvarint(x uint64) {
+	n := binary.PutUvarint(e.scratch[:], x)
+	e.buf.Write(e.scratch[:n])
+}
+
+func (e *encoder) varint(x int64) {
+	n := binary.PutVarint(e.scratch[:], x)
+	e.buf.Write(e.scratch[:n])
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf.WriteString(s)
+}
+
+func (e *encoder) series(ts *TimeSeries) {
+	e.string(ts.key)
+	e.string(ts.alias)
+
+	start, step := ts.start.Unix(), int64(ts.step)
+	e.varint(start - e.start)
+	e.uvarint(uint64(ts.start.Nanosecond()))
+	e.varint(step - e.step)
+	e.start, e.step = start, step
+
+	binary.BigEndian.PutUint64(e.scratch[:8], math.Float64bits(ts.filler))
+	e.buf.Write(e.scratch[:8])
+
+	names := make([]string, 0, len(ts.labels))
+	for k := range ts.labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	e.uvarint(uint64(len(names)))
+	for _, k := range names {
+		e.string(k)
+		e.string(ts.labels[k])
+	}
+
+	e.uvarint(uint64(len(ts.data)))
+	values := encodeXOR(ts.data)
+	e.uvarint(uint64(len(values)))
+	e.buf.Write(values)
+}
+
+type decoder struct {
+	buf     *bytes.Reader
+	version byte
+
+	start int64
+	step  int64
+}
+
+func newDecoder(data []byte) (*decoder, error) {
+	if len(data) == 0 {
+		return nil, errShortBuffer
+	}
+	if data[0] < 1 || data[0] > encodingVersion {
+		return nil, fmt.Errorf("unknown time series encoding version %d", data[0])
+	}
+	return &decoder{buf: bytes.NewReader(data[1:]), version: data[0]}, nil
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	x, err := binary.ReadUvarint(d.buf)
+	if err != nil {
+		return 0, errShortBuffer
+	}
+	return x, nil
+}
+
+func (d *decoder) varint() (int64, error) {
+	x, err := binary.ReadVarint(d.buf)
+	if err != nil {
+		return 0, errShortBuffer
+	}
+	return x, nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(d.buf.Len()) {
+		return nil, errShortBuffer
+	}
+	b := make([]byte, n)
+	d.buf.Read(b)
+	return b, nil
+}
+
+func (d *decoder) string() (string, error) {
+	b, err := d.bytes()
+	return string(b), err
+}
+
+func (d *decoder) series() (*TimeSeries, error) {
+	ts := &TimeSeries{shared: new(atomic.Bool)}
+
+	var err error
+	if ts.key, err = d.string(); err != nil {
+		return nil, err
+	}
+	if d.version >= 2 {
+		if ts.alias, err = d.string(); err != nil {
+			return nil, err
+		}
+	}
+
+	start, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	nsec, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	step, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	d.start += start
+	d.step += step
+	ts.start = time.Unix(d.start, int64(nsec)).UTC()
+	ts.step = time.Duration(d.step)
+	if ts.step == 0 {
+		return nil, fmt.Errorf("time series '%s' step can't be 0", ts.key)
+	}
+
+	var filler [8]byte
+	if n, _ := d.buf.Read(filler[:]); n != len(filler) {
+		return nil, errShortBuffer
+	}
+	ts.filler = math.Float64frombits(binary.BigEndian.Uint64(filler[:]))
+
+	labels, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if labels > 0 {
+		ts.labels = make(Labels)
+	}
+	for i := uint64(0); i < labels; i++ {
+		k, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if ts.labels[k], err = d.string(); err != nil {
+			return nil, err
+		}
+	}
+
+	length, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	values, err := d.bytes()
+	if err != nil {
+		return nil, err
+	}
+	if ts.data, err = decodeXOR(values, length); err != nil {
+		return nil, fmt.Errorf("time series '%s': %s", ts.key, err)
+	}
+	return ts, nil
+}
+
+// encodeXOR writes the first value as is, then for each value the XOR with
+// the previous value:
+//
+//	'0' if the XOR is 0,
+//	'10' followed by the meaningful bits if they fit in the previous window,
+//	'11' followed by 5 bits of leading zeros, 6 bits of meaningful bit count
+//	and the meaningful bits otherwise.
+func encodeXOR(data []float64) []byte {
+	w := &bitWriter{}
+	if len(data) == 0 {
+		return w.b
+	}
+
+	prev := math.Float64bits(data[0])
+	w.writeBits(prev, 64)
+	leading, trailing := -1, 0
+
+	for _, v := range data[1:] {
+		cur := math.Float64bits(v)
+		xor := cur ^ prev
+		prev = cur
+
+		if xor == 0 {
+			w.writeBit(false)
+			continue
+		}
+		w.writeBit(true)
+
+		l, t := bits.LeadingZeros64(xor), bits.TrailingZeros64(xor)
+		if l > 31 {
+			l = 31
+		}
+		if leading != -1 && l >= leading && t >= trailing {
+			w.writeBit(false)
+			w.writeBits(xor>>uint(trailing), 64-leading-trailing)
+			continue
+		}
+
+		leading, trailing = l, t
+		meaningful := 64 - leading - trailing
+		w.writeBit(true)
+		w.writeBits(uint64(leading), 5)
+		// 64 meaningful bits don't fit in 6 bits and are written as 0.
+		w.writeBits(uint64(meaningful&63), 6)
+		w.writeBits(xor>>uint(trailing), meaningful)
+	}
+	return w.b
+}
+
+func decodeXOR(b []byte, length uint64) ([]float64, error) {
+	if length == 0 {
+		return []float64{}, nil
+	}
+	// a value takes at least a bit, so the length can't be larger.
+	if length > uint64(len(b))*8 {
+		return nil, errShortBuffer
+	}
+
+	r := &bitReader{b: b}
+	data := make([]float64, length)
+
+	prev, err := r.readBits(64)
+	if err != nil {
+		return nil, err
+	}
+	data[0] = math.Float64frombits(prev)
+	leading, trailing := -1, 0
+
+	for i := 1; i < len(data); i++ {
+		changed, err := r.readBit()
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			newWindow, err := r.readBit()
+			if err != nil {
+				return nil, err
+			}
+			if newWindow {
+				l, err := r.readBits(5)
+				if err != nil {
+					return nil, err
+				}
+				m, err := r.readBits(6)
+				if err != nil {
+					return nil, err
+				}
+				if m == 0 {
+					m = 64
+				}
+				if int(l+m) > 64 {
+					return nil, errors.New("invalid XOR window")
+				}
+				leading, trailing = int(l), 64-int(l+m)
+			} else if leading == -1 {
+				return nil, errors.New("XOR window used before being set")
+			}
+
+			xor, err := r.readBits(64 - leading - trailing)
+			if err != nil {
+				return nil, err
+			}
+			prev ^= xor << uint(trailing)
+		}
+		data[i] = math.Float64frombits(prev)
+	}
+	return data, nil
+}
+
+type bitWriter struct {
+	b    []byte
+	free uint
+}
+
+func (w *bitWriter) writeBit(bit bool) {
+	if w.free == 0 {
+		w.b = append(w.b, 0)
+		w.free = 8
+	}
+	w.free--
+	if bit {
+		w.b[len(w.b)-1] |= 1 << w.free
+	}
+}
+
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.writeBit(v>>uint(i)&1 == 1)
+	}
+}
+
+type bitReader struct {
+	b   []byte
+	pos uint
+}
+
+func (r *bitReader) readBit() (bool, error) {
+	if r.pos >= uint(len(r.b))*8 {
+		return false, errShortBuffer
+	}
+	bit := r.b[r.pos/8]>>(7-r.pos%8)&1 == 1
+	r.pos++
+	return bit, nil
+}
+
+func (r *bitReader) readBits(n int) (uint64, error) {
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		v <<= 1
+		if bit {
+			v |= 1
+		}
+	}
+	return v, nil
+}
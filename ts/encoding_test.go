@@ -0,0 +1,93 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func checkBits(t *testing.T, got, exp []float64) {
+	if len(got) != len(exp) {
+		t.Errorf("FAIL(data): length '%d' != '%d'", len(got), len(exp))
+		return
+	}
+	for i, g := range got {
+		if math.Float64bits(g) != math.Float64bits(exp[i]) {
+			t.Errorf("FAIL(data): at index: '%d', '%x' != '%x'",
+				i, math.Float64bits(g), math.Float64bits(exp[i]))
+		}
+	}
+}
+
+func TestTimeSeriesBinary(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 500, time.UTC)
+	step := 10 * time.Second
+	payloadNaN := math.Float64frombits(0x7ff8000000000bad)
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step,
+		[]float64{1, 1, 1.5, NaN, payloadNaN, -2, math.Inf(1), 0, 1e-300, math.MaxFloat64})
+	checkErr(t, err)
+	ts0.SetLabel("host", "a")
+
+	ts1, err := NewTimeSeriesOfData("test1", start.Add(-time.Hour), time.Minute, []float64{})
+	checkErr(t, err)
+
+	ts2, err := NewTimeSeriesOfLength("test2", start, step, 1000, 42)
+	checkErr(t, err)
+	ts2.SetAlias("alias2")
+
+	for _, ts := range []*TimeSeries{ts0, ts1, ts2} {
+		b, err := ts.MarshalBinary()
+		checkErr(t, err)
+
+		got := &TimeSeries{}
+		checkErr(t, got.UnmarshalBinary(b))
+		checkTimeSeries(t, got, ts)
+		checkBits(t, got.data, ts.data)
+		if math.Float64bits(got.filler) != math.Float64bits(ts.filler) {
+			t.Errorf("FAIL(filler): got: '%f', expected '%f'", got.filler, ts.filler)
+		}
+		if len(got.labels) != len(ts.labels) || got.labels["host"] != ts.labels["host"] {
+			t.Errorf("FAIL(labels): got: '%v', expected '%v'", got.labels, ts.labels)
+		}
+		if got.Alias() != ts.Alias() {
+			t.Errorf("FAIL(alias): got: '%s', expected '%s'", got.Alias(), ts.Alias())
+		}
+
+		for i := 0; i < len(b); i++ {
+			if err := (&TimeSeries{}).UnmarshalBinary(b[:i]); err == nil {
+				t.Errorf("FAIL(%s): truncated data at %d should fail", ts.key, i)
+				break
+			}
+		}
+	}
+
+	if b, _ := ts2.MarshalBinary(); len(b) > 200 {
+		t.Errorf("FAIL(size): constant series encoded in %d bytes", len(b))
+	}
+
+	tss := TimeSeriesSlice{*ts0, *ts1, *ts2}
+	b, err := tss.MarshalBinary()
+	checkErr(t, err)
+
+	var got TimeSeriesSlice
+	checkErr(t, got.UnmarshalBinary(b))
+	if len(got) != len(tss) {
+		t.Fatalf("FAIL(length): got: '%d', expected '%d'", len(got), len(tss))
+	}
+	for i, _ := range tss {
+		checkTimeSeries(t, &got[i], &tss[i])
+		checkBits(t, got[i].data, tss[i].data)
+		if got[i].Alias() != tss[i].Alias() {
+			t.Errorf("FAIL(alias): got: '%s', expected '%s'", got[i].Alias(), tss[i].Alias())
+		}
+	}
+
+	// version 1 had no alias.
+	v1 := []byte{1, 1, 'x', 2, 0, 2, 0x7f, 0xf8, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	old := &TimeSeries{}
+	checkErr(t, old.UnmarshalBinary(v1))
+	checkTimeSeries(t, old, &TimeSeries{key: "x", start: time.Unix(1, 0).UTC(), step: 1, data: []float64{}})
+}
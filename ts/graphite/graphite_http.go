@@ -96,6 +96,25 @@ func (req *Request) GetQuery() url.Values {
 	return query
 }
 
+// WithHistory returns the request with From moved back by shift, to fetch
+// the baseline of a comparison with the series shifted by shift. A zero From
+// is graphite's default of 24 hours before Until, or before now.
+func (req *Request) WithHistory(shift time.Duration) *Request {
+	from := req.From
+	if from.IsZero() {
+		until := req.Until
+		if until.IsZero() {
+			until = time.Now()
+		}
+		from = until.Add(-24 * time.Hour)
+	}
+	return &Request{
+		Key:   req.Key,
+		From:  from.Add(-shift),
+		Until: req.Until,
+	}
+}
+
 type Requests []Request
 
 func (reqs Requests) GetQuery() url.Values {
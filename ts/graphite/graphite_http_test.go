@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -67,3 +68,17 @@ func TestGraphiteHTTP(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRequestWithHistory(t *testing.T) {
+	req := &Request{Key: "some.random.key", From: start, Until: end}
+	query := req.WithHistory(7 * 24 * time.Hour).GetQuery()
+
+	from := strconv.FormatInt(start.Add(-7*24*time.Hour).Unix(), 10)
+	if query.Get("from") != from {
+		t.Errorf("FAIL(from): got: '%s', expected '%s'", query.Get("from"), from)
+	}
+	until := strconv.FormatInt(end.Unix(), 10)
+	if query.Get("until") != until {
+		t.Errorf("FAIL(until): got: '%s', expected '%s'", query.Get("until"), until)
+	}
+}
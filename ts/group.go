@@ -0,0 +1,178 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GroupByNode combines the series sharing the same dot separated node of
+// their key, like graphite's groupByNode. Negative nodes count from the end
+// of the key. The groups are keyed by the node and ordered by first
+// appearance.
+func (tss TimeSeriesSlice) GroupByNode(node int, transform TranformSlice) (TimeSeriesSlice, error) {
+	return tss.GroupByNodes(transform, node)
+}
+
+// GroupByNodes is GroupByNode with the groups keyed by several nodes joined
+// by dots, like graphite's groupByNodes.
+func (tss TimeSeriesSlice) GroupByNodes(transform TranformSlice, nodes ...int) (TimeSeriesSlice, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("grouping needs at least one node")
+	}
+	return tss.groupBy(transform, func(key string) (string, error) {
+		return keyNodes(key, nodes)
+	})
+}
+
+// GroupByKeyPattern combines the series by the submatches of pattern in their
+// key joined by dots, or by the whole match if it has no group. Series not
+// matching pattern are left out.
+func (tss TimeSeriesSlice) GroupByKeyPattern(pattern string, transform TranformSlice) (TimeSeriesSlice, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return tss.groupBy(transform, func(key string) (string, error) {
+		match := re.FindStringSubmatch(key)
+		if match == nil {
+			return "", nil
+		}
+		if len(match) == 1 {
+			return match[0], nil
+		}
+		return strings.Join(match[1:], "."), nil
+	})
+}
+
+// groupBy combines the series by the group returned by groupOf, series with an
+// empty group are left out.
+func (tss TimeSeriesSlice) groupBy(transform TranformSlice, groupOf func(key string) (string, error)) (TimeSeriesSlice, error) {
+	if transform == nil {
+		return nil, fmt.Errorf("group transform can't be nil")
+	}
+	groups := []string{}
+	members := make(map[string]TimeSeriesSlice)
+	for i, _ := range tss {
+		group, err := groupOf(tss[i].key)
+		if err != nil {
+			return nil, err
+		}
+		if group == "" {
+			continue
+		}
+		if _, ok := members[group]; !ok {
+			groups = append(groups, group)
+		}
+		members[group] = append(members[group], tss[i])
+	}
+
+	res := make(TimeSeriesSlice, 0, len(groups))
+	for _, group := range groups {
+		ts := members[group].TransformSlice(transform)
+		if ts == nil {
+			return nil, fmt.Errorf("can't combine the series of group '%s'", group)
+		}
+		ts.key = group
+		res = append(res, *ts)
+	}
+	return res, nil
+}
+
+// keyNodes returns the dot separated nodes of the path of key joined by dots.
+func keyNodes(key string, nodes []int) (string, error) {
+	parts := strings.Split(path(key), ".")
+	res := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		i := node
+		if i < 0 {
+			i += len(parts)
+		}
+		if i < 0 || i >= len(parts) {
+			return "", fmt.Errorf("key '%s' has no node %d", key, node)
+		}
+		res = append(res, parts[i])
+	}
+	return strings.Join(res, "."), nil
+}
+
+// path returns the metric path of a key wrapped by functions, without its
+// ;tags. It is the first argument holding a path in the last group of
+// parentheses, the key of Name(args)(key) or the series of graphite's
+// name(series,args): the path of MovingAverage(10m)(servers.a.cpu) is
+// servers.a.cpu and that of scale(sumSeries(a.b;tag=v,c.d),2) is a.b.
+func path(key string) string {
+	i := strings.Index(key, "(")
+	if i == -1 {
+		if j := strings.Index(key, ";"); j != -1 {
+			key = key[:j]
+		}
+		return strings.TrimSpace(key)
+	}
+
+	groups := parenGroups(key[i:])
+	if len(groups) == 0 {
+		return key
+	}
+	for _, arg := range splitArgs(groups[len(groups)-1]) {
+		p := path(arg)
+		if p == "" || strings.ContainsAny(p[:1], "\"'") {
+			continue
+		}
+		if _, err := strconv.ParseFloat(p, 64); err == nil {
+			continue
+		}
+		return p
+	}
+	return ""
+}
+
+// parenGroups returns the contents of the consecutive groups of parentheses
+// s starts with, nil if they aren't balanced.
+func parenGroups(s string) []string {
+	res := []string{}
+	for len(s) > 0 && s[0] == '(' {
+		depth, end := 0, -1
+		for i := 0; i < len(s) && end == -1; i++ {
+			switch s[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end == -1 {
+			return nil
+		}
+		res = append(res, s[1:end])
+		s = s[end+1:]
+	}
+	return res
+}
+
+// splitArgs splits s on the commas that aren't nested in parentheses or in
+// the braces of a glob.
+func splitArgs(s string) []string {
+	res := []string{}
+	depth, from := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[from:i])
+				from = i + 1
+			}
+		}
+	}
+	return append(res, s[from:])
+}
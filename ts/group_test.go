@@ -0,0 +1,130 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGroupBy(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	tss := TimeSeriesSlice{}
+	for i, key := range []string{
+		"servers.dc1-web1.cpu.user",
+		"servers.dc2-web1.cpu.user",
+		"servers.dc1-web2.cpu.system",
+		"MovingAverage(10m)(servers.dc1-web3.cpu.user)",
+	} {
+		ts, err := NewTimeSeriesOfData(key, start, step, []float64{float64(i + 1), NaN})
+		checkErr(t, err)
+		tss = append(tss, *ts)
+	}
+
+	group := func(res TimeSeriesSlice, err error) TimeSeriesSlice {
+		checkErr(t, err)
+		return res
+	}
+
+	tests := []struct {
+		Got TimeSeriesSlice
+		Exp TimeSeriesSlice
+	}{
+		{
+			Got: group(tss.GroupByNode(-1, sum{})),
+			Exp: TimeSeriesSlice{
+				{key: "user", start: start, step: step, data: []float64{7, NaN}},
+				{key: "system", start: start, step: step, data: []float64{3, NaN}},
+			},
+		},
+		{
+			Got: group(tss.GroupByNodes(sum{}, 2, 3)),
+			Exp: TimeSeriesSlice{
+				{key: "cpu.user", start: start, step: step, data: []float64{7, NaN}},
+				{key: "cpu.system", start: start, step: step, data: []float64{3, NaN}},
+			},
+		},
+		{
+			Got: group(tss.GroupByKeyPattern(`servers\.(dc\d)-`, sum{})),
+			Exp: TimeSeriesSlice{
+				{key: "dc1", start: start, step: step, data: []float64{8, NaN}},
+				{key: "dc2", start: start, step: step, data: []float64{2, NaN}},
+			},
+		},
+		{
+			Got: group(tss.GroupByKeyPattern(`web2`, sum{})),
+			Exp: TimeSeriesSlice{
+				{key: "web2", start: start, step: step, data: []float64{3, NaN}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		fmt.Printf("%s\n%s\n\n", test.Got.Key(), test.Exp.Key())
+		if len(test.Got) != len(test.Exp) {
+			t.Errorf("FAIL(groups): got '%d' groups, expected '%d'", len(test.Got), len(test.Exp))
+			continue
+		}
+		for i, _ := range test.Exp {
+			checkTimeSeries(t, &test.Got[i], &test.Exp[i])
+		}
+	}
+
+	// the path of graphite keys is their first series argument without tags.
+	graphite := TimeSeriesSlice{}
+	for i, key := range []string{
+		"sumSeries(x.b,c.d)",
+		"scale(x.c.e,2)",
+		"x.b;tag=v",
+	} {
+		ts, err := NewTimeSeriesOfData(key, start, step, []float64{float64(i + 1), NaN})
+		checkErr(t, err)
+		graphite = append(graphite, *ts)
+	}
+	got := group(graphite.GroupByNode(1, sum{}))
+	exp := TimeSeriesSlice{
+		{key: "b", start: start, step: step, data: []float64{4, NaN}},
+		{key: "c", start: start, step: step, data: []float64{2, NaN}},
+	}
+	if len(got) != len(exp) {
+		t.Errorf("FAIL(groups): got '%d' groups, expected '%d'", len(got), len(exp))
+	} else {
+		for i, _ := range exp {
+			checkTimeSeries(t, &got[i], &exp[i])
+		}
+	}
+
+	if _, err := tss.GroupByNode(4, sum{}); err == nil {
+		t.Errorf("FAIL(error): keys have no node 4")
+	}
+	if _, err := tss.GroupByKeyPattern(`(`, sum{}); err == nil {
+		t.Errorf("FAIL(error): pattern is invalid")
+	}
+}
+
+func TestKeyPath(t *testing.T) {
+	tests := []struct {
+		Key  string
+		Path string
+	}{
+		{Key: "a.b.c", Path: "a.b.c"},
+		{Key: "MovingAverage(10m)(servers.a.cpu)", Path: "servers.a.cpu"},
+		{Key: "sumSeries(a.b,c.d)", Path: "a.b"},
+		{Key: "scale(a.b.c,2)", Path: "a.b.c"},
+		{Key: "a.b;tag=v", Path: "a.b"},
+		{Key: "scale(sumSeries(a.b;tag=v,c.d),2)", Path: "a.b"},
+		{Key: "sumSeries(a.{b,c}.d)", Path: "a.{b,c}.d"},
+		{Key: "alias(a.b,'x')", Path: "a.b"},
+		{Key: "Percentile(95)(Sum(a.b,c.d))", Path: "a.b"},
+		{Key: "constantLine(5)", Path: ""},
+	}
+
+	for _, test := range tests {
+		if got := path(test.Key); got != test.Path {
+			t.Errorf("FAIL(path): of '%s' got '%s', expected '%s'", test.Key, got, test.Path)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Interpolation selects how a value is computed between two points.
+type Interpolation int
+
+const (
+	// InterpolateLinear interpolates between the two surrounding points, the
+	// value is held if one of them isn't known.
+	InterpolateLinear Interpolation = iota
+	// InterpolateNearest takes the value of the closest point.
+	InterpolateNearest
+	// InterpolateStep holds the value of the previous point, like GetAt.
+	InterpolateStep
+)
+
+func (i Interpolation) String() string {
+	switch i {
+	case InterpolateLinear:
+		return "Linear"
+	case InterpolateNearest:
+		return "Nearest"
+	case InterpolateStep:
+		return "Step"
+	default:
+		return fmt.Sprintf("Interpolation(%d)", int(i))
+	}
+}
+
+// GetInterpolated returns the value at any t in [Start, End), it is false
+// outside of that range.
+func (ts *TimeSeries) GetInterpolated(t time.Time, interpolation Interpolation) (float64, bool) {
+	if t.Before(ts.start) || !t.Before(ts.End()) {
+		return math.NaN(), false
+	}
+	index := int(t.Sub(ts.start) / ts.step)
+	offset := t.Sub(ts.start.Add(time.Duration(index) * ts.step))
+	prev := ts.data[index]
+	if offset == 0 || index+1 >= len(ts.data) {
+		return prev, true
+	}
+	next := ts.data[index+1]
+
+	switch interpolation {
+	case InterpolateNearest:
+		if offset*2 < ts.step {
+			return prev, true
+		}
+		return next, true
+	case InterpolateStep:
+		return prev, true
+	default:
+		if math.IsNaN(prev) || math.IsNaN(next) {
+			return prev, true
+		}
+		return prev + (next-prev)*float64(offset)/float64(ts.step), true
+	}
+}
+
+// Upsample returns the series over the same range with a smaller step, the
+// new points are interpolated.
+func (ts *TimeSeries) Upsample(step time.Duration, interpolation Interpolation) (*TimeSeries, error) {
+	if step <= 0 || step > ts.step {
+		return nil, fmt.Errorf("upsampling step %v must be positive and at most %v", step, ts.step)
+	}
+	key := fmt.Sprintf("Upsample(%v,%s)(%s)", step, interpolation, ts.key)
+	end := ts.End()
+	points := (end.Sub(ts.start) + step - 1) / step
+	res, err := NewTimeSeries(key, ts.start, ts.start.Add(points*step), step, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+	res.filler = ts.filler
+	res.labels = ts.labels.Copy()
+	ts.interpolateInto(res, interpolation)
+	return res, nil
+}
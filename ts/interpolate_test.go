@@ -0,0 +1,53 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUpsample(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+	third := 10.0 / 3
+
+	ts0, err := NewTimeSeriesOfData("ts0", start, step, []float64{0, 10, NaN, 30})
+	checkErr(t, err)
+
+	tests := []struct {
+		Interpolation Interpolation
+		Exp           []float64
+	}{
+		{InterpolateLinear, []float64{0, third, 2 * third, 10, 10, 10, NaN, NaN, NaN, 30, 30, 30}},
+		{InterpolateNearest, []float64{0, 0, 10, 10, 10, NaN, NaN, NaN, 30, 30, 30, 30}},
+		{InterpolateStep, []float64{0, 0, 0, 10, 10, 10, NaN, NaN, NaN, 30, 30, 30}},
+	}
+
+	for _, test := range tests {
+		got, err := ts0.Upsample(20*time.Second, test.Interpolation)
+		checkErr(t, err)
+		exp := &TimeSeries{
+			key:   fmt.Sprintf("Upsample(20s,%s)(ts0)", test.Interpolation),
+			start: start,
+			step:  20 * time.Second,
+			data:  test.Exp,
+		}
+		fmt.Printf("%s\n%s\n\n", got, exp)
+		checkTimeSeries(t, got, exp)
+	}
+
+	if v, ok := ts0.GetInterpolated(start.Add(30*time.Second), InterpolateLinear); !ok || v != 5 {
+		t.Errorf("FAIL(interpolated): got: '%f', expected '5'", v)
+	}
+	if v, ok := ts0.GetInterpolated(start.Add(3*step+30*time.Second), InterpolateLinear); !ok || v != 30 {
+		t.Errorf("FAIL(interpolated): got: '%f', expected '30'", v)
+	}
+	if _, ok := ts0.GetInterpolated(start.Add(4*step), InterpolateLinear); ok {
+		t.Errorf("FAIL(interpolated): end is out of the series")
+	}
+	if _, err := ts0.Upsample(2*step, InterpolateLinear); err == nil {
+		t.Errorf("FAIL(error): upsampling to a larger step should fail")
+	}
+}
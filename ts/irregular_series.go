@@ -0,0 +1,349 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Point is a single timestamped value of an IrregularSeries.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Resampling selects how the points of an IrregularSeries falling in the same
+// step are reduced to a single value when converted to a TimeSeries.
+type Resampling int
+
+const (
+	// ResampleLast keeps the last non NaN value of the step.
+	ResampleLast Resampling = iota
+	// ResampleMean averages the non NaN values of the step.
+	ResampleMean
+	// ResampleSum sums the non NaN values of the step.
+	ResampleSum
+	// ResampleTimeWeighted considers each value held until the next point
+	// and averages the values weighted by the time they were held during the
+	// step. The last value is held until the end of the series.
+	ResampleTimeWeighted
+)
+
+func (r Resampling) String() string {
+	switch r {
+	case ResampleLast:
+		return "Last"
+	case ResampleMean:
+		return "Mean"
+	case ResampleSum:
+		return "Sum"
+	case ResampleTimeWeighted:
+		return "TimeWeighted"
+	default:
+		return "Resampling(" + strconv.Itoa(int(r)) + ")"
+	}
+}
+
+// NewIrregularSeries creates a series of explicit points, the points don't
+// need to be ordered.
+func NewIrregularSeries(key string, points ...Point) *IrregularSeries {
+	is := &IrregularSeries{
+		key:    key,
+		points: make([]Point, len(points)),
+	}
+	copy(is.points, points)
+	sort.Stable(byTime(is.points))
+	return is
+}
+
+// IrregularSeries is a series of (time, value) points with no fixed step, the
+// points are kept ordered by time.
+type IrregularSeries struct {
+	key    string
+	points []Point
+}
+
+func (is *IrregularSeries) Key() string {
+	return is.key
+}
+func (is *IrregularSeries) SetKey(key string) {
+	is.key = key
+}
+func (is *IrregularSeries) Len() int {
+	return len(is.points)
+}
+
+// Start returns the time of the first point, or the zero time if empty.
+func (is *IrregularSeries) Start() time.Time {
+	if len(is.points) == 0 {
+		return time.Time{}
+	}
+	return is.points[0].Time
+}
+
+// End returns the time of the last point, or the zero time if empty.
+func (is *IrregularSeries) End() time.Time {
+	if len(is.points) == 0 {
+		return time.Time{}
+	}
+	return is.points[len(is.points)-1].Time
+}
+func (is *IrregularSeries) Points() []Point {
+	points := make([]Point, len(is.points))
+	copy(points, is.points)
+	return points
+}
+func (is *IrregularSeries) Copy() *IrregularSeries {
+	return &IrregularSeries{
+		key:    is.key,
+		points: is.Points(),
+	}
+}
+
+// Add inserts a point, keeping the points ordered. A point added at the time
+// of existing points is placed after them.
+func (is *IrregularSeries) Add(t time.Time, value float64) {
+	i := sort.Search(len(is.points), func(i int) bool {
+		return is.points[i].Time.After(t)
+	})
+	is.points = append(is.points, Point{})
+	copy(is.points[i+1:], is.points[i:])
+	is.points[i] = Point{Time: t, Value: value}
+}
+
+// GetAt returns the value of the last point at exactly t.
+func (is *IrregularSeries) GetAt(t time.Time) (float64, bool) {
+	i := sort.Search(len(is.points), func(i int) bool {
+		return is.points[i].Time.After(t)
+	})
+	if i == 0 || !is.points[i-1].Time.Equal(t) {
+		return math.NaN(), false
+	}
+	return is.points[i-1].Value, true
+}
+
+// InferStep guesses the step of the series as the median of the distances
+// between consecutive points, points at the same time are ignored.
+func (is *IrregularSeries) InferStep() (time.Duration, bool) {
+	deltas := make([]int64, 0, len(is.points))
+	for i := 1; i < len(is.points); i++ {
+		if d := is.points[i].Time.Sub(is.points[i-1].Time); d > 0 {
+			deltas = append(deltas, int64(d))
+		}
+	}
+	if len(deltas) == 0 {
+		return 0, false
+	}
+	sort.Sort(int64s(deltas))
+	return time.Duration(deltas[len(deltas)/2]), true
+}
+
+// Resample converts the series to a TimeSeries of the given step, aligned on
+// multiples of the step. If step is 0 it is inferred from the data. Steps
+// without any point are NaN.
+func (is *IrregularSeries) Resample(step time.Duration, resampling Resampling) (*TimeSeries, error) {
+	if len(is.points) == 0 {
+		return nil, fmt.Errorf("can't resample empty series '%s'", is.key)
+	}
+	if step == 0 {
+		var ok bool
+		if step, ok = is.InferStep(); !ok {
+			return nil, fmt.Errorf("step of series '%s' can't be inferred", is.key)
+		}
+	}
+	if step < 0 {
+		return nil, fmt.Errorf("step can't be negative")
+	}
+
+	start := is.Start().Truncate(step)
+	end := is.End().Truncate(step).Add(step)
+	ts, err := NewTimeSeriesOfTimeRange(is.key, start, end, step, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+
+	switch resampling {
+	case ResampleLast, ResampleMean, ResampleSum:
+		is.resampleBuckets(ts, resampling)
+	case ResampleTimeWeighted:
+		is.resampleTimeWeighted(ts)
+	default:
+		return nil, fmt.Errorf("unknown resampling '%s'", resampling)
+	}
+	return ts, nil
+}
+
+func (is *IrregularSeries) resampleBuckets(ts *TimeSeries, resampling Resampling) {
+	var count int
+	var acc float64
+	for i := 0; i < len(is.points); {
+		bucket := is.points[i].Time.Truncate(ts.step)
+		count, acc = 0, 0
+		for ; i < len(is.points) && is.points[i].Time.Truncate(ts.step).Equal(bucket); i++ {
+			v := is.points[i].Value
+			if math.IsNaN(v) {
+				continue
+			}
+			count++
+			if resampling == ResampleLast {
+				acc = v
+			} else {
+				acc += v
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		if resampling == ResampleMean {
+			acc /= float64(count)
+		}
+		ts.SetAt(bucket, acc)
+	}
+}
+
+func (is *IrregularSeries) resampleTimeWeighted(ts *TimeSeries) {
+	end := ts.End()
+	// until is the end of the time a point is held for.
+	until := func(j int) time.Time {
+		if j+1 < len(is.points) {
+			return is.points[j+1].Time
+		}
+		return end
+	}
+
+	// first is the first point held past the start of the bucket, both the
+	// points and the buckets are sorted so it only moves forward.
+	first := 0
+	for i, _ := range ts.data {
+		bStart := ts.start.Add(time.Duration(i) * ts.step)
+		bEnd := bStart.Add(ts.step)
+		for first < len(is.points) && !until(first).After(bStart) {
+			first++
+		}
+
+		var weighted float64
+		var held time.Duration
+		for j := first; j < len(is.points); j++ {
+			p := is.points[j]
+			if !p.Time.Before(bEnd) {
+				break
+			}
+			if math.IsNaN(p.Value) {
+				continue
+			}
+			from, to := p.Time, until(j)
+			if from.Before(bStart) {
+				from = bStart
+			}
+			if to.After(bEnd) {
+				to = bEnd
+			}
+			d := to.Sub(from)
+			weighted += p.Value * float64(d)
+			held += d
+		}
+		if held > 0 {
+			ts.data[i] = weighted / float64(held)
+		}
+	}
+}
+
+func (is IrregularSeries) String() string {
+	s := bytes.NewBufferString("")
+	s.WriteString(is.key)
+
+	s.WriteString(" Start: ")
+	s.WriteString(is.Start().String())
+
+	s.WriteString(" End: ")
+	s.WriteString(is.End().String())
+
+	s.WriteString(" Length: ")
+	s.WriteString(strconv.Itoa(len(is.points)))
+
+	s.WriteString(" ")
+
+	for _, p := range is.points {
+		s.WriteString(p.Time.Format(time.RFC3339))
+		s.WriteByte('=')
+		s.WriteString(strconv.FormatFloat(p.Value, 'f', 2, 64))
+		s.WriteByte(',')
+	}
+	if s.Len() > 0 {
+		s.Truncate(s.Len() - 1)
+	}
+	return s.String()
+}
+
+func (is *IrregularSeries) Iterator() *IrregularIterator {
+	return &IrregularIterator{
+		series: is,
+	}
+}
+
+type IrregularIterator struct {
+	cursor int
+	series *IrregularSeries
+}
+
+func (it *IrregularIterator) Next() (val float64, ok bool) {
+	_, val, ok = it.next()
+	return
+}
+
+func (it *IrregularIterator) Last() (val float64, ok bool) {
+	_, val, ok = it.last()
+	return
+}
+
+func (it *IrregularIterator) next() (t time.Time, val float64, ok bool) {
+	if it.cursor >= len(it.series.points) {
+		return time.Time{}, math.NaN(), false
+	}
+	p := it.series.points[it.cursor]
+	it.cursor++
+	return p.Time, p.Value, true
+}
+
+func (it *IrregularIterator) last() (t time.Time, val float64, ok bool) {
+	if len(it.series.points) == 0 {
+		return time.Time{}, math.NaN(), false
+	}
+	it.cursor = len(it.series.points) - 1
+	return it.next()
+}
+
+type IrregularIteratorTimeValue struct {
+	IrregularIterator
+}
+
+func (is *IrregularSeries) IteratorTimeValue() *IrregularIteratorTimeValue {
+	return &IrregularIteratorTimeValue{IrregularIterator{
+		series: is,
+	}}
+}
+
+func (it *IrregularIteratorTimeValue) Next() (t time.Time, val float64, ok bool) {
+	return it.next()
+}
+
+func (it *IrregularIteratorTimeValue) Last() (t time.Time, val float64, ok bool) {
+	return it.last()
+}
+
+type byTime []Point
+
+func (p byTime) Len() int           { return len(p) }
+func (p byTime) Less(i, j int) bool { return p[i].Time.Before(p[j].Time) }
+func (p byTime) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,81 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIrregularSeriesResample(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	is := NewIrregularSeries("irregular",
+		Point{start.Add(90 * time.Second), 6},
+		Point{start, 2},
+		Point{start.Add(195 * time.Second), 8},
+	)
+	is.Add(start.Add(30*time.Second), 4)
+
+	if inferred, ok := is.InferStep(); !ok || inferred != step {
+		t.Errorf("FAIL(step): got: '%s', expected '%s'", inferred, step)
+	}
+
+	tests := []struct {
+		Resampling Resampling
+		Exp        []float64
+	}{
+		{ResampleLast, []float64{4, 6, NaN, 8}},
+		{ResampleMean, []float64{3, 6, NaN, 8}},
+		{ResampleSum, []float64{6, 6, NaN, 8}},
+		{ResampleTimeWeighted, []float64{3, 5, 6, 7.5}},
+	}
+
+	for _, test := range tests {
+		got, err := is.Resample(0, test.Resampling)
+		checkErr(t, err)
+		exp := &TimeSeries{
+			key:   "irregular",
+			start: start,
+			step:  step,
+			data:  test.Exp,
+		}
+		fmt.Printf("%s %s\n%s\n\n", test.Resampling, got, exp)
+		checkTimeSeries(t, got, exp)
+	}
+}
+
+func TestIrregularSeriesIterator(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+
+	is := NewIrregularSeries("irregular",
+		Point{start.Add(time.Minute), 2},
+		Point{start, 1},
+		Point{start.Add(time.Hour), NaN},
+	)
+
+	expTimes := []time.Time{start, start.Add(time.Minute), start.Add(time.Hour)}
+	expValues := []float64{1, 2, NaN}
+
+	times := []time.Time{}
+	values := []float64{}
+	it := is.IteratorTimeValue()
+	for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+		times = append(times, t)
+		values = append(values, v)
+	}
+	checkData(t, values, expValues)
+	for i, e := range expTimes {
+		checkStart(t, times[i], e)
+	}
+
+	if last, ok := is.Iterator().Last(); !ok || !math.IsNaN(last) {
+		t.Errorf("FAIL(last): got: '%f', expected NaN", last)
+	}
+	if _, ok := NewIrregularSeries("empty").Iterator().Last(); ok {
+		t.Errorf("FAIL(last): empty series can't have a last value")
+	}
+}
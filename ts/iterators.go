@@ -0,0 +1,111 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"iter"
+	"time"
+)
+
+// IteratorOptions bounds and orders an iteration, the zero value iterates
+// forward over the whole series.
+type IteratorOptions struct {
+	// From is the first time included, zero for the start of the series.
+	From time.Time
+	// Until is the first time excluded, zero for the end of the series.
+	Until time.Time
+	// Reverse iterates from the newest point to the oldest.
+	Reverse bool
+	// SkipNaN doesn't return the NaN values.
+	SkipNaN bool
+}
+
+func newIterator(series iterable, opts IteratorOptions) *Iterator {
+	it := &Iterator{
+		series:  series,
+		from:    opts.From,
+		until:   opts.Until,
+		reverse: opts.Reverse,
+		skipNaN: opts.SkipNaN,
+	}
+
+	start, step := series.Start(), series.Step()
+	if opts.Reverse {
+		it.cursor = series.End().Add(-step)
+		if !opts.Until.IsZero() && !opts.Until.After(it.cursor) {
+			// last point before until.
+			points := (opts.Until.Sub(start) + step - 1) / step
+			it.cursor = start.Add((points - 1) * step)
+		}
+	} else {
+		it.cursor = start
+		if opts.From.After(start) {
+			// first point from from.
+			points := (opts.From.Sub(start) + step - 1) / step
+			it.cursor = start.Add(points * step)
+		}
+	}
+	return it
+}
+
+func iterate(series iterable, opts IteratorOptions) iter.Seq2[time.Time, float64] {
+	return func(yield func(time.Time, float64) bool) {
+		it := newIterator(series, opts)
+		for t, v, ok := it.next(); ok; t, v, ok = it.next() {
+			if !yield(t, v) {
+				return
+			}
+		}
+	}
+}
+
+func (ts *TimeSeries) IteratorWith(opts IteratorOptions) *IteratorTimeValue {
+	return &IteratorTimeValue{*newIterator(ts, opts)}
+}
+
+// Seq iterates over the times and values of the series:
+//
+//	for t, v := range series.Seq(IteratorOptions{SkipNaN: true}) {
+func (ts *TimeSeries) Seq(opts IteratorOptions) iter.Seq2[time.Time, float64] {
+	return iterate(ts, opts)
+}
+
+func (ts *TimeSeries) All() iter.Seq2[time.Time, float64] {
+	return iterate(ts, IteratorOptions{})
+}
+
+// Range iterates over the points in [from, until).
+func (ts *TimeSeries) Range(from, until time.Time) iter.Seq2[time.Time, float64] {
+	return iterate(ts, IteratorOptions{From: from, Until: until})
+}
+
+func (ts *TimeSeries) Backward() iter.Seq2[time.Time, float64] {
+	return iterate(ts, IteratorOptions{Reverse: true})
+}
+
+func (rs *RollingSeries) IteratorWith(opts IteratorOptions) *IteratorTimeValue {
+	return &IteratorTimeValue{*newIterator(rs, opts)}
+}
+
+func (rs *RollingSeries) Seq(opts IteratorOptions) iter.Seq2[time.Time, float64] {
+	return iterate(rs, opts)
+}
+
+func (rs *RollingSeries) All() iter.Seq2[time.Time, float64] {
+	return iterate(rs, IteratorOptions{})
+}
+
+// All iterates over a snapshot of the series.
+func (cs *ConcurrentSeries) All() iter.Seq2[time.Time, float64] {
+	return cs.Snapshot().All()
+}
+
+func (is *IrregularSeries) All() iter.Seq2[time.Time, float64] {
+	return func(yield func(time.Time, float64) bool) {
+		for _, p := range is.points {
+			if !yield(p.Time, p.Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"iter"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesSeq(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+	at := func(points int) time.Time {
+		return start.Add(time.Duration(points) * step)
+	}
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, NaN, 3, 4, 5})
+	checkErr(t, err)
+
+	tests := []struct {
+		Seq    iter.Seq2[time.Time, float64]
+		Times  []time.Time
+		Values []float64
+	}{
+		{
+			Seq:    ts0.All(),
+			Times:  []time.Time{at(0), at(1), at(2), at(3), at(4)},
+			Values: []float64{1, NaN, 3, 4, 5},
+		},
+		{
+			Seq:    ts0.Range(start.Add(30*time.Second), at(3)),
+			Times:  []time.Time{at(1), at(2)},
+			Values: []float64{NaN, 3},
+		},
+		{
+			Seq:    ts0.Backward(),
+			Times:  []time.Time{at(4), at(3), at(2), at(1), at(0)},
+			Values: []float64{5, 4, 3, NaN, 1},
+		},
+		{
+			Seq:    ts0.Seq(IteratorOptions{From: at(1), Until: at(4), Reverse: true, SkipNaN: true}),
+			Times:  []time.Time{at(3), at(2)},
+			Values: []float64{4, 3},
+		},
+		{
+			Seq:    ts0.Seq(IteratorOptions{Until: start, Reverse: true}),
+			Times:  []time.Time{},
+			Values: []float64{},
+		},
+		{
+			Seq:    ts0.Range(at(-10), at(10)),
+			Times:  []time.Time{at(0), at(1), at(2), at(3), at(4)},
+			Values: []float64{1, NaN, 3, 4, 5},
+		},
+	}
+
+	for _, test := range tests {
+		times := []time.Time{}
+		values := []float64{}
+		for t, v := range test.Seq {
+			times = append(times, t)
+			values = append(values, v)
+		}
+		checkData(t, values, test.Values)
+		if len(times) != len(test.Times) {
+			continue
+		}
+		for i, e := range test.Times {
+			checkStart(t, times[i], e)
+		}
+	}
+
+	count := 0
+	for range ts0.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("FAIL(break): got '%d' iterations", count)
+	}
+
+	it := ts0.IteratorWith(IteratorOptions{From: at(2), SkipNaN: true})
+	got := []float64{}
+	for _, v, ok := it.Next(); ok; _, v, ok = it.Next() {
+		got = append(got, v)
+	}
+	checkData(t, got, []float64{3, 4, 5})
+}
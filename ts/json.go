@@ -0,0 +1,224 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// JSON encoding of time series in the graphite render format=json shape:
+// [{"target": "key", "datapoints": [[value, timestamp], ...]}, ...]
+// NaN and infinite values are written as null, timestamps are unix seconds.
+// Labels are written in the "tags" object as graphite does for tagged series.
+// The target is the alias of the series as in graphite, when it has one the
+// key is kept in a "key" field that graphite doesn't have.
+
+type jsonSeries struct {
+	Target     string       `json:"target"`
+	Key        string       `json:"key,omitempty"`
+	Tags       Labels       `json:"tags,omitempty"`
+	Datapoints [][]*float64 `json:"datapoints"`
+}
+
+func (ts TimeSeries) MarshalJSON() ([]byte, error) {
+	s := &bytes.Buffer{}
+	if err := ts.writeJSON(s); err != nil {
+		return nil, err
+	}
+	return s.Bytes(), nil
+}
+
+func (ts *TimeSeries) writeJSON(s *bytes.Buffer) error {
+	target, err := json.Marshal(ts.Alias())
+	if err != nil {
+		return err
+	}
+	s.WriteString(`{"target":`)
+	s.Write(target)
+
+	if ts.alias != "" {
+		key, err := json.Marshal(ts.key)
+		if err != nil {
+			return err
+		}
+		s.WriteString(`,"key":`)
+		s.Write(key)
+	}
+
+	if len(ts.labels) > 0 {
+		tags, err := json.Marshal(ts.labels)
+		if err != nil {
+			return err
+		}
+		s.WriteString(`,"tags":`)
+		s.Write(tags)
+	}
+
+	s.WriteString(`,"datapoints":[`)
+	cursor := ts.start
+	for i, v := range ts.data {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		s.WriteByte('[')
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			s.WriteString("null")
+		} else {
+			s.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		s.WriteByte(',')
+		s.WriteString(strconv.FormatInt(cursor.Unix(), 10))
+		s.WriteByte(']')
+		cursor = cursor.Add(ts.step)
+	}
+	s.WriteString("]}")
+	return nil
+}
+
+// UnmarshalJSON reads a series in the graphite json format. The step is the
+// distance between the first two datapoints, a series with less than two
+// datapoints keeps the step it already had.
+func (ts *TimeSeries) UnmarshalJSON(data []byte) error {
+	var js jsonSeries
+	if err := json.Unmarshal(data, &js); err != nil {
+		return err
+	}
+	res, err := js.timeSeries(ts.step)
+	if err != nil {
+		return err
+	}
+	*ts = *res
+	return nil
+}
+
+func (tss TimeSeriesSlice) MarshalJSON() ([]byte, error) {
+	s := bytes.NewBufferString("[")
+	for i, _ := range tss {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		if err := tss[i].writeJSON(s); err != nil {
+			return nil, err
+		}
+	}
+	s.WriteByte(']')
+	return s.Bytes(), nil
+}
+
+// UnmarshalJSON reads a graphite json response, series with less than two
+// datapoints take the step of the other series.
+func (tss *TimeSeriesSlice) UnmarshalJSON(data []byte) error {
+	var jss []jsonSeries
+	if err := json.Unmarshal(data, &jss); err != nil {
+		return err
+	}
+
+	var step time.Duration
+	for _, js := range jss {
+		if s, ok := js.step(); ok {
+			step = s
+			break
+		}
+	}
+
+	res := make(TimeSeriesSlice, 0, len(jss))
+	for _, js := range jss {
+		ts, err := js.timeSeries(step)
+		if err != nil {
+			return err
+		}
+		res = append(res, *ts)
+	}
+	*tss = res
+	return nil
+}
+
+func (js *jsonSeries) timestamp(i int) (time.Time, error) {
+	if len(js.Datapoints[i]) != 2 || js.Datapoints[i][1] == nil {
+		return time.Time{}, fmt.Errorf("target '%s': datapoint %d isn't a [value, timestamp] pair", js.Target, i)
+	}
+	return time.Unix(int64(*js.Datapoints[i][1]), 0).UTC(), nil
+}
+
+func (js *jsonSeries) step() (time.Duration, bool) {
+	if len(js.Datapoints) < 2 {
+		return 0, false
+	}
+	first, err := js.timestamp(0)
+	if err != nil {
+		return 0, false
+	}
+	second, err := js.timestamp(1)
+	if err != nil {
+		return 0, false
+	}
+	return second.Sub(first), true
+}
+
+// keyAlias returns the key and alias of the series, the target is the key of a
+// series without a key field.
+func (js *jsonSeries) keyAlias() (string, string) {
+	if js.Key == "" {
+		return js.Target, ""
+	}
+	return js.Key, js.Target
+}
+
+func (js *jsonSeries) timeSeries(step time.Duration) (*TimeSeries, error) {
+	if s, ok := js.step(); ok {
+		step = s
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("target '%s': step can't be determined", js.Target)
+	}
+
+	key, alias := js.keyAlias()
+
+	// an empty target has no start, it is kept at the zero time rather than
+	// the time.Now() of NewTimeSeriesOfData.
+	if len(js.Datapoints) == 0 {
+		return &TimeSeries{
+			key:    key,
+			alias:  alias,
+			step:   step,
+			data:   []float64{},
+			filler: math.NaN(),
+			labels: js.Tags,
+			shared: new(atomic.Bool),
+		}, nil
+	}
+	start, err := js.timestamp(0)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]float64, len(js.Datapoints))
+	for i, _ := range js.Datapoints {
+		t, err := js.timestamp(i)
+		if err != nil {
+			return nil, err
+		}
+		if !t.Equal(start.Add(time.Duration(i) * step)) {
+			return nil, fmt.Errorf("target '%s': datapoint %d at %v doesn't follow step %v", js.Target, i, t, step)
+		}
+		if v := js.Datapoints[i][0]; v != nil {
+			data[i] = *v
+		} else {
+			data[i] = math.NaN()
+		}
+	}
+
+	ts, err := NewTimeSeriesOfData(key, start, step, data)
+	if err != nil {
+		return nil, err
+	}
+	ts.labels = js.Tags
+	ts.alias = alias
+	return ts, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesJSON(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 15, 17, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("some.random.key", start, step, []float64{1, NaN, 1.5})
+	checkErr(t, err)
+	ts1, err := NewLabeledTimeSeriesOfData(Labels{NameLabel: "cpu", "host": "a"}, start, step, []float64{2})
+	checkErr(t, err)
+
+	got, err := json.Marshal(TimeSeriesSlice{*ts0, *ts1})
+	checkErr(t, err)
+	exp := `[{"target":"some.random.key","datapoints":[[1,1452877200],[null,1452877260],[1.5,1452877320]]},` +
+		`{"target":"cpu;host=a","tags":{"host":"a","name":"cpu"},"datapoints":[[2,1452877200]]}]`
+	if string(got) != exp {
+		t.Errorf("FAIL(json): got:\n\t%s\nexpected:\n\t%s", got, exp)
+	}
+
+	var tss TimeSeriesSlice
+	checkErr(t, json.Unmarshal(got, &tss))
+	if len(tss) != 2 {
+		t.Fatalf("FAIL(length): got: '%d', expected '2'", len(tss))
+	}
+	checkTimeSeries(t, &tss[0], ts0)
+	checkTimeSeries(t, &tss[1], ts1)
+	if host, _ := tss[1].Label("host"); host != "a" {
+		t.Errorf("FAIL(labels): got: '%v', expected '%v'", tss[1].labels, ts1.labels)
+	}
+
+	// the alias is the graphite target, the key is kept aside.
+	aliased := ts0.Copy()
+	aliased.SetAlias("random")
+	got, err = json.Marshal(aliased)
+	checkErr(t, err)
+	exp = `{"target":"random","key":"some.random.key","datapoints":[[1,1452877200],[null,1452877260],[1.5,1452877320]]}`
+	if string(got) != exp {
+		t.Errorf("FAIL(json): got:\n\t%s\nexpected:\n\t%s", got, exp)
+	}
+	unaliased := &TimeSeries{}
+	checkErr(t, json.Unmarshal(got, unaliased))
+	checkTimeSeries(t, unaliased, ts0)
+	if unaliased.Alias() != "random" {
+		t.Errorf("FAIL(alias): got: '%s', expected 'random'", unaliased.Alias())
+	}
+
+	single := &TimeSeries{}
+	checkErr(t, json.Unmarshal([]byte(`{"target":"x","datapoints":[[null,60],[3,120]]}`), single))
+	checkTimeSeries(t, single, &TimeSeries{
+		key:   "x",
+		start: time.Unix(60, 0).UTC(),
+		step:  step,
+		data:  []float64{NaN, 3},
+	})
+
+	checkErr(t, json.Unmarshal([]byte(`[{"target":"x","datapoints":[[1,60],[2,120]]},{"target":"y","datapoints":[]}]`), &tss))
+	checkTimeSeries(t, &tss[1], &TimeSeries{
+		key:  "y",
+		step: step,
+		data: []float64{},
+	})
+
+	for _, invalid := range []string{
+		`{"target":"x","datapoints":[[1,60]]}`,
+		`{"target":"x","datapoints":[[1,60],[2,120],[3,240]]}`,
+		`{"target":"x","datapoints":[[1]]}`,
+	} {
+		if err := json.Unmarshal([]byte(invalid), &TimeSeries{}); err == nil {
+			t.Errorf("FAIL(%s): expected an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NameLabel is the label holding the metric name, as in graphite tags.
+const NameLabel = "name"
+
+// Labels are the dimensions of a time series, like host, dc or service.
+type Labels map[string]string
+
+func (l Labels) Copy() Labels {
+	if l == nil {
+		return nil
+	}
+	c := make(Labels, len(l))
+	for k, v := range l {
+		c[k] = v
+	}
+	return c
+}
+
+// Key renders the labels in the graphite tagged series format:
+// name;tag1=value1;tag2=value2 with the tags ordered by name.
+func (l Labels) Key() string {
+	names := make([]string, 0, len(l))
+	for k := range l {
+		if k != NameLabel {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	key := l[NameLabel]
+	for _, k := range names {
+		key += ";" + k + "=" + l[k]
+	}
+	return key
+}
+
+// ParseLabels parses a key in the graphite tagged series format, a key
+// without tags only has the name label.
+func ParseLabels(key string) Labels {
+	parts := strings.Split(key, ";")
+	labels := Labels{NameLabel: parts[0]}
+	for _, tag := range parts[1:] {
+		if i := strings.Index(tag, "="); i > 0 {
+			labels[tag[:i]] = tag[i+1:]
+		}
+	}
+	return labels
+}
+
+// NewLabeledTimeSeries is NewTimeSeries with a key rendered from the labels.
+func NewLabeledTimeSeries(labels Labels, start, end time.Time, step time.Duration, values ...float64) (*TimeSeries, error) {
+	ts, err := NewTimeSeries(labels.Key(), start, end, step, values...)
+	if err != nil {
+		return nil, err
+	}
+	ts.labels = labels.Copy()
+	return ts, nil
+}
+
+func NewLabeledTimeSeriesOfData(labels Labels, start time.Time, step time.Duration, data []float64) (*TimeSeries, error) {
+	return NewLabeledTimeSeries(labels, start, time.Time{}, step, data...)
+}
+
+func (ts *TimeSeries) Labels() Labels {
+	return ts.labels.Copy()
+}
+
+// SetLabels replaces the labels, the key is left untouched.
+func (ts *TimeSeries) SetLabels(labels Labels) {
+	ts.labels = labels.Copy()
+}
+
+func (ts *TimeSeries) Label(name string) (string, bool) {
+	v, ok := ts.labels[name]
+	return v, ok
+}
+
+// SetLabel sets a single label, the key is left untouched.
+func (ts *TimeSeries) SetLabel(name, value string) {
+	labels := ts.labels.Copy()
+	if labels == nil {
+		labels = make(Labels)
+	}
+	labels[name] = value
+	ts.labels = labels
+}
+
+// MatchOp is the comparison of a label matcher.
+type MatchOp string
+
+const (
+	MatchEqual     MatchOp = "="
+	MatchNotEqual  MatchOp = "!="
+	MatchRegexp    MatchOp = "=~"
+	MatchNotRegexp MatchOp = "!~"
+)
+
+// Matcher compares a single label, a missing label is matched as "".
+type Matcher struct {
+	Name  string
+	Op    MatchOp
+	Value string
+
+	re *regexp.Regexp
+}
+
+func NewMatcher(name string, op MatchOp, value string) (*Matcher, error) {
+	m := &Matcher{
+		Name:  name,
+		Op:    op,
+		Value: value,
+	}
+	switch op {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, err
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("unknown match operator '%s'", op)
+	}
+	return m, nil
+}
+
+func (m *Matcher) Matches(labels Labels) bool {
+	v := labels[m.Name]
+	switch m.Op {
+	case MatchEqual:
+		return v == m.Value
+	case MatchNotEqual:
+		return v != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(v)
+	case MatchNotRegexp:
+		return !m.re.MatchString(v)
+	}
+	return false
+}
+
+func (m *Matcher) String() string {
+	return m.Name + string(m.Op) + strconv.Quote(m.Value)
+}
+
+// Selector matches labels when all its matchers do.
+type Selector []*Matcher
+
+func (sel Selector) Matches(labels Labels) bool {
+	for _, m := range sel {
+		if !m.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (sel Selector) String() string {
+	s := make([]string, len(sel))
+	for i, m := range sel {
+		s[i] = m.String()
+	}
+	return strings.Join(s, ",")
+}
+
+// ParseSelector parses a comma separated list of matchers like
+// service="api",dc=~"us-.*", optionally surrounded by braces.
+func ParseSelector(s string) (Selector, error) {
+	in := strings.TrimSpace(s)
+	if strings.HasPrefix(in, "{") && strings.HasSuffix(in, "}") {
+		in = in[1 : len(in)-1]
+	}
+
+	sel := Selector{}
+	for in = strings.TrimSpace(in); in != ""; {
+		i := 0
+		for i < len(in) && isLabelChar(in[i]) {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("selector '%s': label name expected at '%s'", s, in)
+		}
+		name := in[:i]
+		in = strings.TrimSpace(in[i:])
+
+		var op MatchOp
+		for _, o := range []MatchOp{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+			if strings.HasPrefix(in, string(o)) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("selector '%s': operator expected at '%s'", s, in)
+		}
+		in = strings.TrimSpace(in[len(op):])
+
+		quoted, err := strconv.QuotedPrefix(in)
+		if err != nil {
+			return nil, fmt.Errorf("selector '%s': quoted value expected at '%s'", s, in)
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, err
+		}
+		in = strings.TrimSpace(in[len(quoted):])
+
+		m, err := NewMatcher(name, op, value)
+		if err != nil {
+			return nil, fmt.Errorf("selector '%s': %s", s, err)
+		}
+		sel = append(sel, m)
+
+		if in == "" {
+			break
+		}
+		if in[0] != ',' {
+			return nil, fmt.Errorf("selector '%s': ',' expected at '%s'", s, in)
+		}
+		in = strings.TrimSpace(in[1:])
+	}
+	return sel, nil
+}
+
+func isLabelChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// GetSelected returns the series whose labels match the selector.
+func (tss TimeSeriesSlice) GetSelected(sel Selector) TimeSeriesSlice {
+	res := TimeSeriesSlice{}
+	for _, ts := range tss {
+		if sel.Matches(ts.labels) {
+			res = append(res, ts)
+		}
+	}
+	return res
+}
+
+// Select parses the selector and returns the matching series.
+func (tss TimeSeriesSlice) Select(selector string) (TimeSeriesSlice, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return tss.GetSelected(sel), nil
+}
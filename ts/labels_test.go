@@ -0,0 +1,80 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLabelsKey(t *testing.T) {
+	labels := Labels{NameLabel: "cpu", "host": "a", "dc": "us-east"}
+	checkKey(t, labels.Key(), "cpu;dc=us-east;host=a")
+
+	parsed := ParseLabels(labels.Key())
+	if len(parsed) != len(labels) {
+		t.Errorf("FAIL(labels): got: '%v', expected '%v'", parsed, labels)
+	}
+	for k, v := range labels {
+		if parsed[k] != v {
+			t.Errorf("FAIL(labels): got: '%v', expected '%v'", parsed, labels)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	start := time.Date(2016, time.Month(2), 1, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	tss := TimeSeriesSlice{}
+	for _, labels := range []Labels{
+		{NameLabel: "latency", "service": "api", "dc": "us-east"},
+		{NameLabel: "latency", "service": "api", "dc": "eu-west"},
+		{NameLabel: "latency", "service": "web", "dc": "us-west"},
+		{NameLabel: "latency", "dc": "us-west"},
+	} {
+		ts, err := NewLabeledTimeSeriesOfData(labels, start, step, []float64{1})
+		checkErr(t, err)
+		tss = append(tss, *ts)
+	}
+
+	tests := []struct {
+		Selector string
+		Exp      []string
+	}{
+		{
+			Selector: `service="api",dc=~"us-.*"`,
+			Exp:      []string{"latency;dc=us-east;service=api"},
+		},
+		{
+			Selector: `{ service != "api" , dc!~"eu.*" }`,
+			Exp:      []string{"latency;dc=us-west;service=web", "latency;dc=us-west"},
+		},
+		{
+			Selector: `service=""`,
+			Exp:      []string{"latency;dc=us-west"},
+		},
+		{
+			Selector: `name="latency",dc=~"us"`,
+			Exp:      []string{},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := tss.Select(test.Selector)
+		checkErr(t, err)
+		if len(got) != len(test.Exp) {
+			t.Errorf("FAIL(%s): got: '%s', expected '%v'", test.Selector, got.Key(), test.Exp)
+			continue
+		}
+		for i, key := range test.Exp {
+			checkKey(t, got[i].Key(), key)
+		}
+	}
+
+	for _, invalid := range []string{`service`, `service=api`, `service="api"dc="x"`, `dc=~"("`} {
+		if _, err := ParseSelector(invalid); err == nil {
+			t.Errorf("FAIL(%s): expected a parse error", invalid)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+)
+
+// Conflict selects the value kept where merged series overlap.
+type Conflict int
+
+const (
+	// PreferLeft keeps the value of the series merged into.
+	PreferLeft Conflict = iota
+	// PreferRight keeps the value of the series merged.
+	PreferRight
+	// PreferNonNaN keeps the left value unless it is NaN.
+	PreferNonNaN
+)
+
+// Merge stitches two series of the same step covering overlapping or
+// adjacent ranges, like two fetches of the same key. Points outside of both
+// series are NaN.
+func (ts *TimeSeries) Merge(other *TimeSeries, conflict Conflict) (*TimeSeries, error) {
+	var combine func(l, r float64) float64
+	switch conflict {
+	case PreferLeft:
+		combine = func(l, r float64) float64 { return l }
+	case PreferRight:
+		combine = func(l, r float64) float64 { return r }
+	case PreferNonNaN:
+		combine = func(l, r float64) float64 {
+			if math.IsNaN(l) {
+				return r
+			}
+			return l
+		}
+	default:
+		return nil, fmt.Errorf("unknown merge conflict %d", conflict)
+	}
+	return ts.merge(other, combine)
+}
+
+// MergeWith is Merge with the overlapping values combined by a TransformPair
+// called with the left and right values.
+func (ts *TimeSeries) MergeWith(other *TimeSeries, combine TransformPair) (*TimeSeries, error) {
+	if combine == nil {
+		return nil, fmt.Errorf("merge transform can't be nil")
+	}
+	return ts.merge(other, combine.TransformPair)
+}
+
+func (ts *TimeSeries) merge(other *TimeSeries, combine func(l, r float64) float64) (*TimeSeries, error) {
+	if other == nil {
+		return nil, fmt.Errorf("merged series can't be nil")
+	}
+	if !ts.IsEqualStep(other) {
+		return nil, fmt.Errorf("can't merge '%s' and '%s', step sizes don't match", ts.key, other.key)
+	}
+	if other.start.Sub(ts.start)%ts.step != 0 {
+		return nil, fmt.Errorf("can't merge '%s' and '%s', steps aren't aligned", ts.key, other.key)
+	}
+
+	key := ts.key
+	if ts.key != other.key {
+		key = fmt.Sprintf("Merge(%s,%s)", ts.key, other.key)
+	}
+	start, end := ts.start, ts.End()
+	if other.start.Before(start) {
+		start = other.start
+	}
+	if other.End().After(end) {
+		end = other.End()
+	}
+	res, err := NewTimeSeries(key, start, end, ts.step, math.NaN())
+	if err != nil {
+		return nil, err
+	}
+	res.filler = ts.filler
+	res.labels = ts.labels.Copy()
+	copy(res.data[ts.start.Sub(start)/ts.step:], ts.data)
+
+	cursor := other.start
+	for _, r := range other.data {
+		if l, ok := ts.GetAt(cursor); ok {
+			r = combine(l, r)
+		}
+		res.SetAt(cursor, r)
+		cursor = cursor.Add(ts.step)
+	}
+	return res, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type averagePair struct{}
+
+func (a averagePair) Name() string {
+	return "AveragePair"
+}
+
+func (a averagePair) TransformPair(f, s float64) float64 {
+	return (f + s) / 2
+}
+
+func TestMerge(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	left, err := NewTimeSeriesOfData("key", start, step, []float64{1, 2, NaN, 4})
+	checkErr(t, err)
+	right, err := NewTimeSeriesOfData("key", start.Add(2*step), step, []float64{30, 40, 5, 6})
+	checkErr(t, err)
+	later, err := NewTimeSeriesOfData("later", start.Add(6*step), step, []float64{7})
+	checkErr(t, err)
+
+	merge := func(res *TimeSeries, err error) *TimeSeries {
+		checkErr(t, err)
+		return res
+	}
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: merge(left.Merge(right, PreferLeft)),
+			Exp: &TimeSeries{key: "key", start: start, step: step, data: []float64{1, 2, NaN, 4, 5, 6}},
+		},
+		{
+			Got: merge(left.Merge(right, PreferRight)),
+			Exp: &TimeSeries{key: "key", start: start, step: step, data: []float64{1, 2, 30, 40, 5, 6}},
+		},
+		{
+			Got: merge(left.Merge(right, PreferNonNaN)),
+			Exp: &TimeSeries{key: "key", start: start, step: step, data: []float64{1, 2, 30, 4, 5, 6}},
+		},
+		{
+			Got: merge(right.Merge(left, PreferNonNaN)),
+			Exp: &TimeSeries{key: "key", start: start, step: step, data: []float64{1, 2, 30, 40, 5, 6}},
+		},
+		{
+			Got: merge(left.MergeWith(right, averagePair{})),
+			Exp: &TimeSeries{key: "key", start: start, step: step, data: []float64{1, 2, NaN, 22, 5, 6}},
+		},
+		{
+			Got: merge(later.Merge(left, PreferLeft)),
+			Exp: &TimeSeries{key: "Merge(later,key)", start: start, step: step, data: []float64{1, 2, NaN, 4, NaN, NaN, 7}},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+
+	offStep, err := NewTimeSeriesOfData("key", start.Add(30*time.Second), step, []float64{1})
+	checkErr(t, err)
+	if _, err := left.Merge(offStep, PreferLeft); err == nil {
+		t.Errorf("FAIL(error): merging unaligned steps should fail")
+	}
+}
@@ -8,8 +8,7 @@ func Any(ts *ts.TimeSeries, predicate func(float64) bool) bool {
 	if ts == nil {
 		return false
 	}
-	it := ts.Iterator()
-	for val, ok := it.Next(); ok; val, ok = it.Next() {
+	for _, val := range ts.All() {
 		if predicate(val) {
 			return true
 		}
@@ -21,8 +20,7 @@ func None(ts *ts.TimeSeries, predicate func(float64) bool) bool {
 	if ts == nil {
 		return false
 	}
-	it := ts.Iterator()
-	for val, ok := it.Next(); ok; val, ok = it.Next() {
+	for _, val := range ts.All() {
 		if predicate(val) {
 			return false
 		}
@@ -0,0 +1,188 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// NewRollingSeries creates an empty series keeping at most capacity points,
+// once full the oldest points are dropped and the start advances as new
+// points are added.
+func NewRollingSeries(key string, start time.Time, step time.Duration, capacity int, filler float64) (*RollingSeries, error) {
+	if int(step) == 0 {
+		return nil, fmt.Errorf("step can't be 0")
+	}
+	if capacity <= 0 {
+		return nil, fmt.Errorf("capacity must be positive, got %d", capacity)
+	}
+	if start.IsZero() {
+		start = time.Now()
+	}
+	return &RollingSeries{
+		key:    key,
+		start:  start,
+		step:   step,
+		data:   make([]float64, capacity),
+		filler: filler,
+	}, nil
+}
+
+// NewRollingSeriesOfDuration keeps the points covering window, like the last
+// 6 hours.
+func NewRollingSeriesOfDuration(key string, start time.Time, step time.Duration, window time.Duration, filler float64) (*RollingSeries, error) {
+	if int(step) == 0 {
+		return nil, fmt.Errorf("step can't be 0")
+	}
+	return NewRollingSeries(key, start, step, int(window/step), filler)
+}
+
+// RollingSeries is a fixed capacity TimeSeries backed by a ring buffer.
+type RollingSeries struct {
+	key    string
+	start  time.Time
+	step   time.Duration
+	filler float64
+
+	data   []float64
+	head   int
+	length int
+}
+
+func (rs *RollingSeries) Key() string {
+	return rs.key
+}
+func (rs *RollingSeries) SetKey(key string) {
+	rs.key = key
+}
+func (rs *RollingSeries) Start() time.Time {
+	return rs.start
+}
+func (rs *RollingSeries) End() time.Time {
+	return rs.start.Add(time.Duration(rs.length) * rs.step)
+}
+func (rs *RollingSeries) Step() time.Duration {
+	return rs.step
+}
+func (rs *RollingSeries) Len() int {
+	return rs.length
+}
+func (rs *RollingSeries) Capacity() int {
+	return len(rs.data)
+}
+
+// Data returns the points from the oldest to the newest.
+func (rs *RollingSeries) Data() []float64 {
+	data := make([]float64, rs.length)
+	n := copy(data, rs.data[rs.head:])
+	if n < rs.length {
+		copy(data[n:], rs.data[:rs.length-n])
+	}
+	return data
+}
+func (rs *RollingSeries) Copy() *RollingSeries {
+	nrs := *rs
+	nrs.data = make([]float64, len(rs.data))
+	copy(nrs.data, rs.data)
+	return &nrs
+}
+
+// TimeSeries returns a copy of the current window as a TimeSeries, to be used
+// with transforms and charts.
+func (rs *RollingSeries) TimeSeries() *TimeSeries {
+	return &TimeSeries{
+		key:    rs.key,
+		start:  rs.start,
+		step:   rs.step,
+		data:   rs.Data(),
+		filler: rs.filler,
+		shared: new(atomic.Bool),
+	}
+}
+
+func (rs *RollingSeries) push(v float64) {
+	if rs.length < len(rs.data) {
+		rs.data[(rs.head+rs.length)%len(rs.data)] = v
+		rs.length++
+		return
+	}
+	rs.data[rs.head] = v
+	rs.head = (rs.head + 1) % len(rs.data)
+	rs.start = rs.start.Add(rs.step)
+}
+
+// ExtendTo adds filler points until t is in the window.
+func (rs *RollingSeries) ExtendTo(t time.Time) {
+	end := rs.End()
+	if t.Before(end) {
+		return
+	}
+	rs.extend(int(t.Sub(end)/rs.step) + 1)
+}
+func (rs *RollingSeries) ExtendBy(d time.Duration) {
+	rs.extend(int(d / rs.step))
+}
+func (rs *RollingSeries) ExtendWith(data ...float64) {
+	for _, v := range data {
+		rs.push(v)
+	}
+}
+
+func (rs *RollingSeries) extend(points int) {
+	// filling more than the capacity only moves the start.
+	if skip := points - len(rs.data); skip > 0 {
+		rs.start = rs.start.Add(time.Duration(rs.length+skip) * rs.step)
+		rs.head, rs.length = 0, 0
+		points = len(rs.data)
+	}
+	for i := 0; i < points; i++ {
+		rs.push(rs.filler)
+	}
+}
+
+func (rs *RollingSeries) index(t time.Time) int {
+	if t.Before(rs.start) || !t.Before(rs.End()) {
+		return -1
+	}
+	i := int(t.Sub(rs.start) / rs.step)
+	return (rs.head + i) % len(rs.data)
+}
+
+func (rs *RollingSeries) GetAt(t time.Time) (float64, bool) {
+	index := rs.index(t)
+	if index == -1 {
+		return math.NaN(), false
+	}
+	return rs.data[index], true
+}
+
+func (rs *RollingSeries) SetAt(t time.Time, value float64) bool {
+	index := rs.index(t)
+	if index == -1 {
+		return false
+	}
+	rs.data[index] = value
+	return true
+}
+
+// Record sets the value at t, extending the window first if t is past its
+// end. It returns false if t is older than the window.
+func (rs *RollingSeries) Record(t time.Time, value float64) bool {
+	rs.ExtendTo(t)
+	return rs.SetAt(t, value)
+}
+
+func (rs *RollingSeries) Iterator() *Iterator {
+	return newIterator(rs, IteratorOptions{})
+}
+
+func (rs *RollingSeries) IteratorTimeValue() *IteratorTimeValue {
+	return &IteratorTimeValue{*newIterator(rs, IteratorOptions{})}
+}
+
+func (rs RollingSeries) String() string {
+	return rs.TimeSeries().String()
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRollingSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	rs, err := NewRollingSeries("rolling", start, step, 3, NaN)
+	checkErr(t, err)
+
+	rs.ExtendWith(1, 2)
+	checkTimeSeries(t, rs.TimeSeries(), &TimeSeries{
+		key: "rolling", start: start, step: step, data: []float64{1, 2},
+	})
+
+	rs.ExtendWith(3, 4)
+	checkTimeSeries(t, rs.TimeSeries(), &TimeSeries{
+		key: "rolling", start: start.Add(step), step: step, data: []float64{2, 3, 4},
+	})
+
+	if _, ok := rs.GetAt(start); ok {
+		t.Errorf("FAIL(GetAt): dropped point can't be found")
+	}
+	if !rs.SetAt(start.Add(2*step), 30) {
+		t.Errorf("FAIL(SetAt): point in the window should be set")
+	}
+
+	cp := rs.Copy()
+
+	if !rs.Record(start.Add(5*step), 6) {
+		t.Errorf("FAIL(Record): point past the window should be recorded")
+	}
+	checkTimeSeries(t, rs.TimeSeries(), &TimeSeries{
+		key: "rolling", start: start.Add(3 * step), step: step, data: []float64{4, NaN, 6},
+	})
+	if rs.Record(start, 1) {
+		t.Errorf("FAIL(Record): point older than the window can't be recorded")
+	}
+
+	checkTimeSeries(t, cp.TimeSeries(), &TimeSeries{
+		key: "rolling", start: start.Add(step), step: step, data: []float64{2, 30, 4},
+	})
+
+	got := []float64{}
+	it := rs.Iterator()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		got = append(got, v)
+	}
+	checkData(t, got, []float64{4, NaN, 6})
+
+	rs.ExtendBy(10 * step)
+	checkTimeSeries(t, rs.TimeSeries(), &TimeSeries{
+		key: "rolling", start: start.Add(13 * step), step: step, data: []float64{NaN, NaN, NaN},
+	})
+	fmt.Println(rs)
+}
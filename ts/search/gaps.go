@@ -0,0 +1,104 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package search
+
+import (
+	"math"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+// Gap is a run of missing points in [Start, End).
+type Gap struct {
+	Start  time.Time
+	End    time.Time
+	Points int
+}
+
+func (gap Gap) Duration() time.Duration {
+	return gap.End.Sub(gap.Start)
+}
+
+// Gaps returns the runs of consecutive points for which missing is true,
+// missing defaults to NaN when nil.
+func Gaps(ts *ts.TimeSeries, missing func(float64) bool) []Gap {
+	if ts == nil {
+		return nil
+	}
+	if missing == nil {
+		missing = math.IsNaN
+	}
+
+	gaps := []Gap{}
+	var gap *Gap
+	it := ts.IteratorTimeValue()
+	for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+		if !missing(v) {
+			gap = nil
+			continue
+		}
+		if gap == nil {
+			gaps = append(gaps, Gap{Start: t})
+			gap = &gaps[len(gaps)-1]
+		}
+		gap.End = t.Add(ts.Step())
+		gap.Points++
+	}
+	return gaps
+}
+
+// Completeness returns the ratio of points of the series that aren't missing,
+// 0 for an empty series. missing defaults as in Gaps.
+func Completeness(ts *ts.TimeSeries, missing func(float64) bool) float64 {
+	present, total := count(ts, missing)
+	if total == 0 {
+		return 0
+	}
+	return float64(present) / float64(total)
+}
+
+// SliceCompleteness returns the ratio of points of all the series of the slice
+// that aren't missing.
+func SliceCompleteness(tss ts.TimeSeriesSlice, missing func(float64) bool) float64 {
+	var present, total int
+	for i, _ := range tss {
+		p, t := count(&tss[i], missing)
+		present += p
+		total += t
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(present) / float64(total)
+}
+
+func count(ts *ts.TimeSeries, missing func(float64) bool) (present, total int) {
+	if ts == nil {
+		return 0, 0
+	}
+	if missing == nil {
+		missing = math.IsNaN
+	}
+	it := ts.IteratorTimeValue()
+	for _, v, ok := it.Next(); ok; _, v, ok = it.Next() {
+		total++
+		if !missing(v) {
+			present++
+		}
+	}
+	return
+}
+
+// NaNOrFiller is a missing predicate true for NaN and for the filler of the
+// series, for series filled with a value that isn't NaN. A series whose values
+// all equal its filler, like one of a single point, is then all missing.
+func NaNOrFiller(ts *ts.TimeSeries) func(float64) bool {
+	filler := ts.Filler()
+	if math.IsNaN(filler) {
+		return math.IsNaN
+	}
+	return func(v float64) bool {
+		return math.IsNaN(v) || v == filler
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+	. "github.com/datacratic/gotsvis/ts/predicate"
+)
+
+func checkErr(t *testing.T, err error) {
+	if err != nil {
+		t.Errorf("FAIL(error): %s", err)
+	}
+}
+
+func TestGaps(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 21, 0, 0, 0, 0, time.UTC)
+	step := time.Hour
+
+	ts1, err := ts.NewTimeSeriesOfData("ts1", start, step, []float64{NaN, 1, NaN, NaN, 0, 2, NaN})
+	checkErr(t, err)
+	ts2, err := ts.NewTimeSeriesOfData("ts2", start, step, []float64{1, 1, 1})
+	checkErr(t, err)
+
+	exp := []Gap{
+		{Start: start, End: start.Add(step), Points: 1},
+		{Start: start.Add(2 * step), End: start.Add(4 * step), Points: 2},
+		{Start: start.Add(6 * step), End: start.Add(7 * step), Points: 1},
+	}
+	got := Gaps(ts1, nil)
+	if len(got) != len(exp) {
+		t.Fatalf("FAIL(gaps): got: '%v', expected '%v'", got, exp)
+	}
+	for i, gap := range got {
+		if gap != exp[i] {
+			t.Errorf("FAIL(gap %d): got: '%v', expected '%v'", i, gap, exp[i])
+		}
+	}
+	if d := got[1].Duration(); d != 2*time.Hour {
+		t.Errorf("FAIL(duration): got: '%s', expected '%s'", d, 2*time.Hour)
+	}
+
+	missing := func(v float64) bool { return EQNAN(v) || EQ(0)(v) }
+	if got := Gaps(ts1, missing); len(got) != 3 || got[1].Points != 3 {
+		t.Errorf("FAIL(gaps): got: '%v'", got)
+	}
+
+	if c := Completeness(ts1, nil); c != 3.0/7 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '%f'", c, 3.0/7)
+	}
+	if c := Completeness(ts2, nil); c != 1 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '1'", c)
+	}
+	if c := SliceCompleteness(ts.TimeSeriesSlice{*ts1, *ts2}, nil); c != 6.0/10 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '%f'", c, 6.0/10)
+	}
+
+	ts3, err := ts.NewTimeSeriesOfTimeRange("ts3", start, start.Add(5*step), step, -1)
+	checkErr(t, err)
+	ts3.SetAt(start.Add(step), 5)
+	ts3.SetAt(start.Add(4*step), 6)
+	exp = []Gap{
+		{Start: start, End: start.Add(step), Points: 1},
+		{Start: start.Add(2 * step), End: start.Add(4 * step), Points: 2},
+	}
+	got = Gaps(ts3, NaNOrFiller(ts3))
+	if len(got) != len(exp) || got[0] != exp[0] || got[1] != exp[1] {
+		t.Errorf("FAIL(gaps): got: '%v', expected '%v'", got, exp)
+	}
+	if c := Completeness(ts3, NaNOrFiller(ts3)); c != 2.0/5 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '%f'", c, 2.0/5)
+	}
+	if c := Completeness(ts3, nil); c != 1 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '1'", c)
+	}
+
+	// the value of a single point is also its filler.
+	ts4, err := ts.NewTimeSeriesOfData("ts4", start, step, []float64{3})
+	checkErr(t, err)
+	if got := Gaps(ts4, nil); len(got) != 0 {
+		t.Errorf("FAIL(gaps): got: '%v', expected none", got)
+	}
+	if c := Completeness(ts4, nil); c != 1 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '1'", c)
+	}
+
+	ts5, err := ts.NewTimeSeriesOfTimeRange("ts5", start, start.Add(4*step), step, 0)
+	checkErr(t, err)
+	if got := Gaps(ts5, nil); len(got) != 0 {
+		t.Errorf("FAIL(gaps): got: '%v', expected none", got)
+	}
+	if c := Completeness(ts5, nil); c != 1 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '1'", c)
+	}
+	if c := Completeness(ts5, NaNOrFiller(ts5)); c != 0 {
+		t.Errorf("FAIL(completeness): got: '%f', expected '0'", c)
+	}
+
+	if gaps := Gaps(nil, nil); gaps != nil {
+		t.Errorf("FAIL(gaps): nil series can't have gaps")
+	}
+}
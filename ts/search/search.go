@@ -15,8 +15,7 @@ func First(ts *ts.TimeSeries, predicate func(float64) bool) (time.Time, float64,
 	if ts == nil {
 		return time.Time{}, NaN, false
 	}
-	it := ts.IteratorTimeValue()
-	for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+	for t, v := range ts.All() {
 		if predicate(v) {
 			return t, v, true
 		}
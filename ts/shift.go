@@ -0,0 +1,55 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// TimeShift moves the series later by d, or earlier if d is negative, so a
+// series of last week shifted by 7 days lines up with this week.
+func (ts *TimeSeries) TimeShift(d time.Duration) *TimeSeries {
+	res := ts.Copy()
+	res.key = fmt.Sprintf("timeShift(%s)(%s)", FormatDuration(d), ts.key)
+	res.alias = ""
+	res.start = ts.start.Add(d)
+	return res
+}
+
+// RatioToShifted divides the series by baseline shifted by d, over the range
+// of the series. Points without a baseline are NaN.
+func (ts *TimeSeries) RatioToShifted(baseline *TimeSeries, d time.Duration) (*TimeSeries, error) {
+	if baseline == nil {
+		return nil, fmt.Errorf("baseline series can't be nil")
+	}
+	return ts.Div(baseline.TimeShift(d), JoinPolicy{Join: LeftJoin})
+}
+
+// DiffToShifted subtracts baseline shifted by d from the series, over the
+// range of the series. Points without a baseline are NaN.
+func (ts *TimeSeries) DiffToShifted(baseline *TimeSeries, d time.Duration) (*TimeSeries, error) {
+	if baseline == nil {
+		return nil, fmt.Errorf("baseline series can't be nil")
+	}
+	return ts.Sub(baseline.TimeShift(d), JoinPolicy{Join: LeftJoin})
+}
+
+// FormatDuration formats d with its trailing zero units dropped and whole
+// days in days, like graphite: 7d, 1h, 10m, 1h30m.
+func FormatDuration(d time.Duration) string {
+	if d != 0 && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeShift(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 4, 0, 0, 0, 0, time.UTC)
+	week := 7 * day
+
+	ts0, err := NewTimeSeriesOfData("key", start, day,
+		[]float64{1, 2, 3, 4, 5, 6, 7, 2, 4, 6, 8, 10, 12, 14})
+	checkErr(t, err)
+
+	shifted := ts0.TimeShift(week)
+	checkKey(t, shifted.Key(), "timeShift(7d)(key)")
+	checkStart(t, shifted.Start(), start.Add(week))
+	checkData(t, shifted.Data(), ts0.Data())
+
+	ratio, err := ts0.RatioToShifted(ts0, week)
+	checkErr(t, err)
+	diff, err := ts0.DiffToShifted(ts0, week)
+	checkErr(t, err)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ratio,
+			Exp: &TimeSeries{key: "Div(key,timeShift(7d)(key))", start: start, step: day,
+				data: []float64{NaN, NaN, NaN, NaN, NaN, NaN, NaN, 2, 2, 2, 2, 2, 2, 2}},
+		},
+		{
+			Got: diff,
+			Exp: &TimeSeries{key: "Sub(key,timeShift(7d)(key))", start: start, step: day,
+				data: []float64{NaN, NaN, NaN, NaN, NaN, NaN, NaN, 1, 2, 3, 4, 5, 6, 7}},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+
+	for d, exp := range map[time.Duration]string{
+		-day:                       "-1d",
+		time.Hour:                  "1h",
+		10 * time.Minute:           "10m",
+		90 * time.Minute:           "1h30m",
+		30 * time.Second:           "30s",
+		25*time.Hour + time.Second: "25h0m1s",
+	} {
+		checkKey(t, FormatDuration(d), exp)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"math"
+	"sort"
+)
+
+// SortByKey sorts the series by key in place, like graphite's sortByName.
+func (tss TimeSeriesSlice) SortByKey() {
+	sort.SliceStable(tss, func(i, j int) bool {
+		return tss[i].key < tss[j].key
+	})
+}
+
+// SortBy sorts the series in place by stat of their non NaN values, in
+// decreasing order if descending. Series without values are last.
+func (tss TimeSeriesSlice) SortBy(stat TranformSlice, descending bool) {
+	stats := make([]float64, len(tss))
+	for i, _ := range tss {
+		stats[i] = tss[i].stat(stat)
+	}
+	sort.Stable(byStat{tss, stats, descending})
+}
+
+// Highest returns the n series with the highest stat, from the highest.
+func (tss TimeSeriesSlice) Highest(n int, stat TranformSlice) TimeSeriesSlice {
+	sorted := append(TimeSeriesSlice{}, tss...)
+	sorted.SortBy(stat, true)
+	return sorted.Limit(n)
+}
+
+// Lowest returns the n series with the lowest stat, from the lowest.
+func (tss TimeSeriesSlice) Lowest(n int, stat TranformSlice) TimeSeriesSlice {
+	sorted := append(TimeSeriesSlice{}, tss...)
+	sorted.SortBy(stat, false)
+	return sorted.Limit(n)
+}
+
+// Limit returns the first n series.
+func (tss TimeSeriesSlice) Limit(n int) TimeSeriesSlice {
+	if n < 0 {
+		n = 0
+	}
+	if n < len(tss) {
+		return tss[:n]
+	}
+	return tss
+}
+
+// stat is the stat of the non NaN values of the series, NaN if it has none.
+func (ts *TimeSeries) stat(stat TranformSlice) float64 {
+	vals := make([]float64, 0, len(ts.data))
+	for _, v := range ts.data {
+		if !math.IsNaN(v) {
+			vals = append(vals, v)
+		}
+	}
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	return stat.TransformSlice(vals)
+}
+
+type byStat struct {
+	tss        TimeSeriesSlice
+	stats      []float64
+	descending bool
+}
+
+func (s byStat) Len() int {
+	return len(s.tss)
+}
+
+func (s byStat) Swap(i, j int) {
+	s.tss[i], s.tss[j] = s.tss[j], s.tss[i]
+	s.stats[i], s.stats[j] = s.stats[j], s.stats[i]
+}
+
+func (s byStat) Less(i, j int) bool {
+	a, b := s.stats[i], s.stats[j]
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return !math.IsNaN(a) && math.IsNaN(b)
+	}
+	if s.descending {
+		return a > b
+	}
+	return a < b
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSort(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+
+	tss := TimeSeriesSlice{}
+	for _, d := range []struct {
+		Key  string
+		Data []float64
+	}{
+		{"d", []float64{0, 10, 0}},
+		{"c", []float64{NaN, NaN, NaN}},
+		{"a", []float64{1, 5, 2}},
+		{"b", []float64{3, 3, NaN}},
+	} {
+		ts, err := NewTimeSeriesOfData(d.Key, start, time.Minute, d.Data)
+		checkErr(t, err)
+		tss = append(tss, *ts)
+	}
+	keys := func(tss TimeSeriesSlice) string {
+		return strings.Replace(tss.Key(), ",", "", -1)
+	}
+
+	highest := tss.Highest(2, sum{})
+	checkKey(t, keys(highest), "da")
+	lowest := tss.Lowest(2, sum{})
+	checkKey(t, keys(lowest), "ba")
+	checkKey(t, keys(tss), "dcab")
+
+	tss.SortByKey()
+	checkKey(t, keys(tss), "abcd")
+	tss.SortBy(sum{}, true)
+	checkKey(t, keys(tss), "dabc")
+	tss.SortBy(sum{}, false)
+	checkKey(t, keys(tss), "badc")
+
+	checkKey(t, keys(tss.Limit(10)), "badc")
+	checkKey(t, keys(tss.Limit(1)), "b")
+	checkKey(t, keys(tss.Limit(-1)), "")
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -41,6 +42,7 @@ func NewTimeSeries(key string, start, end time.Time, step time.Duration, values
 		start:  start,
 		step:   step,
 		filler: filler,
+		shared: new(atomic.Bool),
 	}
 
 	if ts.start.After(end) {
@@ -69,7 +71,7 @@ func NewTimeSeries(key string, start, end time.Time, step time.Duration, values
 }
 
 func NewTimeSeriesOfTimeRange(key string, start, end time.Time, step time.Duration, filler float64) (*TimeSeries, error) {
-	return NewTimeSeries(key, start, end.Add(step), step, filler)
+	return NewTimeSeries(key, start, end, step, filler)
 }
 
 func NewTimeSeriesOfLength(key string, start time.Time, step time.Duration, length int, filler float64) (*TimeSeries, error) {
@@ -86,6 +88,14 @@ type TimeSeries struct {
 	step   time.Duration
 	data   []float64
 	filler float64
+	labels Labels
+	// alias is the name of the series in charts, the key if empty.
+	alias string
+
+	// shared is set when data is shared with a view and must be copied
+	// before being modified. The flag is held by the series and its views,
+	// so taking a view doesn't modify the series.
+	shared *atomic.Bool
 }
 
 func (ts *TimeSeries) Key() string {
@@ -103,6 +113,9 @@ func (ts *TimeSeries) End() time.Time {
 func (ts *TimeSeries) Step() time.Duration {
 	return ts.step
 }
+func (ts *TimeSeries) Filler() float64 {
+	return ts.filler
+}
 func (ts *TimeSeries) Data() []float64 {
 	data := make([]float64, len(ts.data))
 	for i, v := range ts.data {
@@ -117,6 +130,9 @@ func (ts *TimeSeries) Copy() *TimeSeries {
 		step:   ts.step,
 		data:   ts.Data(),
 		filler: ts.filler,
+		labels: ts.labels.Copy(),
+		alias:  ts.alias,
+		shared: new(atomic.Bool),
 	}
 	return nts
 }
@@ -143,6 +159,39 @@ func (ts *TimeSeries) ExtendWith(data ...float64) {
 	ts.data = append(ts.data, data...)
 }
 
+// ExtendBackTo adds filler points before the start until t is in the series.
+func (ts *TimeSeries) ExtendBackTo(t time.Time) {
+	if !t.Before(ts.start) {
+		return
+	}
+	points := (ts.start.Sub(t) + ts.step - 1) / ts.step
+	ts.extendBack(int(points))
+}
+func (ts *TimeSeries) ExtendBackBy(d time.Duration) {
+	ts.extendBack(int(d / ts.step))
+}
+
+// PrependWith adds the data before the start, moving the start back.
+func (ts *TimeSeries) PrependWith(data ...float64) {
+	ndata := make([]float64, len(data)+len(ts.data))
+	copy(ndata, data)
+	copy(ndata[len(data):], ts.data)
+	ts.data = ndata
+	ts.shared = new(atomic.Bool)
+	ts.start = ts.start.Add(-time.Duration(len(data)) * ts.step)
+}
+
+func (ts *TimeSeries) extendBack(points int) {
+	if points <= 0 {
+		return
+	}
+	data := make([]float64, points)
+	for i, _ := range data {
+		data[i] = ts.filler
+	}
+	ts.PrependWith(data...)
+}
+
 func (ts *TimeSeries) index(t time.Time) int {
 	if t.Before(ts.start) {
 		return -1
@@ -172,6 +221,7 @@ func (ts *TimeSeries) SetAt(t time.Time, value float64) bool {
 		return false
 	}
 
+	ts.own()
 	ts.data[index] = value
 	return true
 }
@@ -183,6 +233,7 @@ func (ts *TimeSeries) IsEqualStep(other *TimeSeries) bool {
 func (ts *TimeSeries) Transform(transform Transform) *TimeSeries {
 	tts := ts.Copy()
 	tts.key = transform.Name() + "(" + ts.key + ")"
+	tts.alias = ""
 
 	for i, v := range tts.data {
 		tts.data[i] = transform.Transform(v)
@@ -191,6 +242,16 @@ func (ts *TimeSeries) Transform(transform Transform) *TimeSeries {
 	return tts
 }
 
+// TransformSeries applies a transform that needs to see all the points, like
+// filling holes from their neighbours.
+func (ts *TimeSeries) TransformSeries(transform TransformSeries) *TimeSeries {
+	tts := ts.Copy()
+	tts.key = transform.Name() + "(" + ts.key + ")"
+	tts.alias = ""
+	transform.TransformSeries(tts.data, tts.step)
+	return tts
+}
+
 func (ts TimeSeries) String() string {
 	s := bytes.NewBufferString("")
 	s.WriteString(ts.key)
@@ -224,50 +285,77 @@ type Transform interface {
 	Transform(float64) float64
 }
 
+// TransformSeries modifies in place the data of a series of the given step.
+type TransformSeries interface {
+	Name() string
+	TransformSeries(data []float64, step time.Duration)
+}
+
 func (ts *TimeSeries) Iterator() *Iterator {
-	return &Iterator{
-		cursor: ts.start,
-		series: ts,
-	}
+	return newIterator(ts, IteratorOptions{})
 }
 
 type Iterator struct {
 	cursor time.Time
-	series *TimeSeries
+	series iterable
+
+	from    time.Time
+	until   time.Time
+	reverse bool
+	skipNaN bool
+}
+
+// iterable is what the iterators need from a series.
+type iterable interface {
+	GetAt(time.Time) (float64, bool)
+	Start() time.Time
+	Step() time.Duration
+	End() time.Time
 }
 
 func (it *Iterator) Next() (val float64, ok bool) {
-	val, ok = it.series.GetAt(it.cursor)
-	it.cursor = it.cursor.Add(it.series.step)
+	_, val, ok = it.next()
 	return
 }
 
 func (it *Iterator) Last() (val float64, ok bool) {
-	it.cursor = it.series.End().Add(-it.series.step)
+	it.cursor = it.series.End().Add(-it.series.Step())
 	val, ok = it.series.GetAt(it.cursor)
 	return
 }
 
+func (it *Iterator) next() (t time.Time, val float64, ok bool) {
+	for {
+		t = it.cursor
+		if (!it.from.IsZero() && t.Before(it.from)) || (!it.until.IsZero() && !t.Before(it.until)) {
+			return t, math.NaN(), false
+		}
+		val, ok = it.series.GetAt(t)
+		if it.reverse {
+			it.cursor = it.cursor.Add(-it.series.Step())
+		} else {
+			it.cursor = it.cursor.Add(it.series.Step())
+		}
+		if !ok || !it.skipNaN || !math.IsNaN(val) {
+			return
+		}
+	}
+}
+
 type IteratorTimeValue struct {
 	Iterator
 }
 
 func (ts *TimeSeries) IteratorTimeValue() *IteratorTimeValue {
-	return &IteratorTimeValue{Iterator{
-		cursor: ts.start,
-		series: ts,
-	}}
+	return &IteratorTimeValue{*newIterator(ts, IteratorOptions{})}
 }
 
 func (it *IteratorTimeValue) Next() (t time.Time, val float64, ok bool) {
-	t = it.cursor
-	val, ok = it.series.GetAt(it.cursor)
-	it.cursor = it.cursor.Add(it.series.step)
-	return
+	return it.next()
 }
 
 func (it *IteratorTimeValue) Last() (t time.Time, val float64, ok bool) {
-	it.cursor = it.series.End().Add(-it.series.step)
+	it.cursor = it.series.End().Add(-it.series.Step())
 	t = it.cursor
 	val, ok = it.series.GetAt(it.cursor)
 	return
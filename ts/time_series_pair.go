@@ -5,6 +5,7 @@ package ts
 import (
 	"fmt"
 	"math"
+	"sync/atomic"
 )
 
 type TimeSeriesPair struct {
@@ -30,10 +31,11 @@ func (tsp *TimeSeriesPair) TransformPair(t TransformPair) *TimeSeries {
 
 	size := end.Sub(start) / step
 	result := &TimeSeries{
-		key:   fmt.Sprintf("%s(%s,%s)", t.Name(), tsp.First.key, tsp.Second.key),
-		start: start,
-		step:  step,
-		data:  make([]float64, size),
+		key:    fmt.Sprintf("%s(%s,%s)", t.Name(), tsp.First.key, tsp.Second.key),
+		start:  start,
+		step:   step,
+		data:   make([]float64, size),
+		shared: new(atomic.Bool),
 	}
 	for i, _ := range result.data {
 		result.data[i] = math.NaN()
@@ -8,6 +8,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -112,10 +113,13 @@ func (tss TimeSeriesSlice) Step() (time.Duration, bool) {
 	return tss.checkEqualStep()
 }
 
+// TransformSlice combines the non NaN values of the series point by point,
+// series with different steps are aligned first. Points without values are
+// NaN, unless the transform is a TransformSliceEmpty.
 func (tss TimeSeriesSlice) TransformSlice(transform TranformSlice) *TimeSeries {
 
-	step, ok := tss.checkEqualStep()
-	if !ok {
+	tss, step, err := tss.aligned()
+	if err != nil {
 		return nil
 	}
 	start, end := tss.getStartEnd()
@@ -130,10 +134,11 @@ func (tss TimeSeriesSlice) TransformSlice(transform TranformSlice) *TimeSeries {
 
 	size := end.Sub(start) / step
 	result := &TimeSeries{
-		key:   transform.Name() + "(" + key + ")",
-		start: start,
-		step:  step,
-		data:  make([]float64, size),
+		key:    transform.Name() + "(" + key + ")",
+		start:  start,
+		step:   step,
+		data:   make([]float64, size),
+		shared: new(atomic.Bool),
 	}
 	for i, _ := range result.data {
 		result.data[i] = math.NaN()
@@ -144,6 +149,7 @@ func (tss TimeSeriesSlice) TransformSlice(transform TranformSlice) *TimeSeries {
 	// it may be a costly iteration method.
 	// A better approach may be to use multiple cursors that are
 	// eventually aligned.
+	empty, hasEmpty := transform.(TransformSliceEmpty)
 	cursor := start
 	slice := make([]float64, 0, len(tss))
 	for i, _ := range result.data {
@@ -158,6 +164,8 @@ func (tss TimeSeriesSlice) TransformSlice(transform TranformSlice) *TimeSeries {
 		}
 		if len(slice) > 0 {
 			result.data[i] = transform.TransformSlice(slice)
+		} else if hasEmpty {
+			result.data[i] = empty.TransformEmpty()
 		}
 		cursor = cursor.Add(step)
 	}
@@ -168,10 +176,12 @@ func (tss TimeSeriesSlice) TransformEach(transform Transform) (TimeSeriesSlice,
 	return nil, nil
 }
 
+// C3Data is the data columns of the series for a c3 chart, or empty if the
+// slice is empty or can't be aligned.
 func (tss TimeSeriesSlice) C3Data() template.JS {
-	step, ok := tss.checkEqualStep()
-	if !ok {
-		panic("step sizes don't match")
+	tss, step, err := tss.aligned()
+	if err != nil {
+		return template.JS("")
 	}
 	start, end := tss.getStartEnd()
 
@@ -180,7 +190,7 @@ func (tss TimeSeriesSlice) C3Data() template.JS {
 		if _, err := s.WriteString("['"); err != nil {
 			panic(err)
 		}
-		if _, err := s.WriteString(tss[i].key); err != nil {
+		if _, err := s.WriteString(tss[i].Alias()); err != nil {
 			panic(err)
 		}
 		if _, err := s.WriteString("', "); err != nil {
@@ -215,10 +225,12 @@ func (tss TimeSeriesSlice) C3Data() template.JS {
 	return template.JS(s.String())
 }
 
+// C3Time is the x column of the series for a c3 chart, or empty if the slice
+// is empty or can't be aligned.
 func (tss TimeSeriesSlice) C3Time() template.JS {
-	step, ok := tss.checkEqualStep()
-	if !ok {
-		panic("step sizes don't match")
+	tss, step, err := tss.aligned()
+	if err != nil {
+		return template.JS("")
 	}
 	start, end := tss.getStartEnd()
 
@@ -244,7 +256,7 @@ func (tss TimeSeriesSlice) C3Time() template.JS {
 }
 
 func (tss TimeSeriesSlice) JSTag() template.JS {
-	return template.JS(tss.Key())
+	return template.JS(strings.Join(tss.Aliases(), ","))
 }
 
 func (tss TimeSeriesSlice) Key() string {
@@ -263,6 +275,13 @@ type TranformSlice interface {
 	TransformSlice([]float64) float64
 }
 
+// TransformSliceEmpty is a TranformSlice with a result for the points where
+// none of the series has a value, like a count of 0.
+type TransformSliceEmpty interface {
+	TranformSlice
+	TransformEmpty() float64
+}
+
 type Filter interface {
 	Filter(float64) bool
 }
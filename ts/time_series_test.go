@@ -192,7 +192,7 @@ func TestTimeSeriesExtend(t *testing.T) {
 	ts2.ExtendTo(time.Date(2016, time.Month(1), 25, 10, 5, 0, 0, time.UTC))
 
 	ts3 := ts0.Copy()
-	ts3.ExtendWith([]float64{4, 5, 6})
+	ts3.ExtendWith([]float64{4, 5, 6}...)
 
 	tss := []struct {
 		Got *TimeSeries
@@ -288,3 +288,67 @@ func TestTimeSeriesIterator(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeSeriesExtendBack(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts0, err := NewTimeSeriesOfData("test0", start, step, []float64{1, 2, 3})
+	checkErr(t, err)
+	ts1 := ts0.Copy()
+	ts1.ExtendBackBy(2 * time.Minute)
+
+	ts2 := ts0.Copy()
+	ts2.ExtendBackTo(start.Add(-90 * time.Second))
+
+	ts3 := ts0.View(start, start.Add(time.Hour))
+	ts3.PrependWith(-1, 0)
+	ts3.SetAt(start, 10)
+
+	tss := []struct {
+		Got *TimeSeries
+		Exp *TimeSeries
+	}{
+		{
+			Got: ts1,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start.Add(-2 * step),
+				step:  step,
+				data:  []float64{NaN, NaN, 1, 2, 3},
+			},
+		},
+		{
+			Got: ts2,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start.Add(-2 * step),
+				step:  step,
+				data:  []float64{NaN, NaN, 1, 2, 3},
+			},
+		},
+		{
+			Got: ts3,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start.Add(-2 * step),
+				step:  step,
+				data:  []float64{-1, 0, 10, 2, 3},
+			},
+		},
+		{
+			Got: ts0,
+			Exp: &TimeSeries{
+				key:   "test0",
+				start: start,
+				step:  step,
+				data:  []float64{1, 2, 3},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
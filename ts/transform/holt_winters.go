@@ -0,0 +1,205 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	. "github.com/datacratic/gotsvis/ts"
+)
+
+// HoltWinters forecasts a seasonal series with triple exponential smoothing,
+// like graphite's holtWinters functions. The first Bootstrap of the series
+// trains the model and is left out of the results, so the series should be
+// fetched with that much extra history, see graphite.Request.WithHistory.
+// Zero values use graphite's defaults: 0.1 for Alpha and Gamma, 0.0035 for
+// Beta, a Season of 1 day, a Bootstrap of 7 days and a Delta of 3 deviations
+// for the confidence bands.
+type HoltWinters struct {
+	Alpha     float64
+	Beta      float64
+	Gamma     float64
+	Season    time.Duration
+	Bootstrap time.Duration
+	Delta     float64
+}
+
+func (hw HoltWinters) withDefaults() HoltWinters {
+	if hw.Alpha == 0 {
+		hw.Alpha = 0.1
+	}
+	if hw.Beta == 0 {
+		hw.Beta = 0.0035
+	}
+	if hw.Gamma == 0 {
+		hw.Gamma = 0.1
+	}
+	if hw.Season == 0 {
+		hw.Season = 24 * time.Hour
+	}
+	if hw.Bootstrap == 0 {
+		hw.Bootstrap = 7 * 24 * time.Hour
+	}
+	if hw.Delta == 0 {
+		hw.Delta = 3
+	}
+	return hw
+}
+
+// analysis returns the predictions and deviations of every point of ts, NaN
+// values are skipped and break the prediction of the next point.
+func (hw HoltWinters) analysis(ts *TimeSeries) (predictions, deviations []float64, err error) {
+	season := int(hw.Season / ts.Step())
+	if season <= 0 {
+		return nil, nil, fmt.Errorf("season %v is shorter than the step %v", hw.Season, ts.Step())
+	}
+	data := ts.Data()
+	intercepts := make([]float64, len(data))
+	slopes := make([]float64, len(data))
+	seasonals := make([]float64, len(data))
+	predictions = make([]float64, len(data))
+	deviations = make([]float64, len(data))
+
+	last := func(values []float64, i int) float64 {
+		if j := i - season; j >= 0 {
+			return values[j]
+		}
+		return 0
+	}
+
+	next := math.NaN()
+	for i, actual := range data {
+		if math.IsNaN(actual) {
+			intercepts[i] = math.NaN()
+			predictions[i] = next
+			next = math.NaN()
+			continue
+		}
+
+		var lastIntercept, lastSlope, prediction float64
+		if i == 0 {
+			lastIntercept = actual
+			prediction = actual
+		} else {
+			lastIntercept = intercepts[i-1]
+			lastSlope = slopes[i-1]
+			if math.IsNaN(lastIntercept) {
+				lastIntercept = actual
+			}
+			prediction = next
+		}
+		lastSeasonal := last(seasonals, i)
+		nextLastSeasonal := last(seasonals, i+1)
+
+		intercept := hw.Alpha*(actual-lastSeasonal) + (1-hw.Alpha)*(lastIntercept+lastSlope)
+		slope := hw.Beta*(intercept-lastIntercept) + (1-hw.Beta)*lastSlope
+		seasonal := hw.Gamma*(actual-intercept) + (1-hw.Gamma)*lastSeasonal
+		next = intercept + slope + nextLastSeasonal
+
+		predicted := prediction
+		if math.IsNaN(predicted) {
+			predicted = 0
+		}
+		deviation := hw.Gamma*math.Abs(actual-predicted) + (1-hw.Gamma)*last(deviations, i)
+
+		intercepts[i] = intercept
+		slopes[i] = slope
+		seasonals[i] = seasonal
+		predictions[i] = prediction
+		deviations[i] = deviation
+	}
+	return predictions, deviations, nil
+}
+
+// series returns the part of data after the bootstrap as a series.
+func (hw HoltWinters) series(key string, ts *TimeSeries, data []float64) (*TimeSeries, error) {
+	bootstrap := int(hw.Bootstrap / ts.Step())
+	start := ts.Start().Add(time.Duration(bootstrap) * ts.Step())
+	return NewTimeSeriesOfData(key, start, ts.Step(), data[bootstrap:])
+}
+
+func (hw HoltWinters) check(ts *TimeSeries) error {
+	if ts == nil {
+		return fmt.Errorf("series can't be nil")
+	}
+	if bootstrap := int(hw.Bootstrap / ts.Step()); bootstrap >= len(ts.Data()) {
+		return fmt.Errorf("'%s' is not longer than the bootstrap %v", ts.Key(), hw.Bootstrap)
+	}
+	return nil
+}
+
+// Forecast returns the predicted values of ts after the bootstrap.
+func (hw HoltWinters) Forecast(ts *TimeSeries) (*TimeSeries, error) {
+	hw = hw.withDefaults()
+	if err := hw.check(ts); err != nil {
+		return nil, err
+	}
+	predictions, _, err := hw.analysis(ts)
+	if err != nil {
+		return nil, err
+	}
+	return hw.series(fmt.Sprintf("holtWintersForecast(%s)", ts.Key()), ts, predictions)
+}
+
+// ConfidenceBands returns the forecast of ts plus and minus Delta times the
+// predicted deviation.
+func (hw HoltWinters) ConfidenceBands(ts *TimeSeries) (upper, lower *TimeSeries, err error) {
+	hw = hw.withDefaults()
+	if err := hw.check(ts); err != nil {
+		return nil, nil, err
+	}
+	upperData, lowerData, err := hw.bands(ts)
+	if err != nil {
+		return nil, nil, err
+	}
+	upper, err = hw.series(fmt.Sprintf("holtWintersConfidenceUpper(%s)", ts.Key()), ts, upperData)
+	if err != nil {
+		return nil, nil, err
+	}
+	lower, err = hw.series(fmt.Sprintf("holtWintersConfidenceLower(%s)", ts.Key()), ts, lowerData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return upper, lower, nil
+}
+
+func (hw HoltWinters) bands(ts *TimeSeries) (upper, lower []float64, err error) {
+	predictions, deviations, err := hw.analysis(ts)
+	if err != nil {
+		return nil, nil, err
+	}
+	upper = make([]float64, len(predictions))
+	lower = make([]float64, len(predictions))
+	for i, prediction := range predictions {
+		upper[i] = prediction + hw.Delta*deviations[i]
+		lower[i] = prediction - hw.Delta*deviations[i]
+	}
+	return upper, lower, nil
+}
+
+// Aberration returns how far the values of ts after the bootstrap are above
+// the upper band, or below the lower band as a negative value, and 0 when
+// they are within the bands or NaN.
+func (hw HoltWinters) Aberration(ts *TimeSeries) (*TimeSeries, error) {
+	hw = hw.withDefaults()
+	if err := hw.check(ts); err != nil {
+		return nil, err
+	}
+	upper, lower, err := hw.bands(ts)
+	if err != nil {
+		return nil, err
+	}
+	aberration := make([]float64, len(upper))
+	for i, actual := range ts.Data() {
+		switch {
+		case math.IsNaN(actual):
+		case actual > upper[i]:
+			aberration[i] = actual - upper[i]
+		case actual < lower[i]:
+			aberration[i] = actual - lower[i]
+		}
+	}
+	return hw.series(fmt.Sprintf("holtWintersAberration(%s)", ts.Key()), ts, aberration)
+}
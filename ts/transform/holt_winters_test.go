@@ -0,0 +1,122 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+// checkClose is checkData with a tolerance, for results that depend on the
+// rounding of long computations.
+func checkClose(t *testing.T, got, exp []float64) {
+	if len(got) != len(exp) {
+		checkData(t, got, exp)
+		return
+	}
+	for i, g := range got {
+		if math.IsNaN(g) != math.IsNaN(exp[i]) || math.Abs(g-exp[i]) > 1e-9 {
+			t.Errorf("FAIL(data): at index: '%d', '%f' != '%f':\ngot:\n\t%v,\nexpected:\n\t%v",
+				i, g, exp[i], got, exp)
+		}
+	}
+}
+
+func TestHoltWinters(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+	NaN := math.NaN()
+
+	ts1, err := ts.NewTimeSeriesOfData("ts1", start, step,
+		[]float64{1, 5, 3, 2, 1, 6, 3, 2, 1, 5, NaN, 2, 1, 5, 3, 20})
+	checkErr(t, err)
+	if ts1 == nil {
+		t.Errorf("FAIL(ts1): can't be nil, if we want to continue with other tests")
+		return
+	}
+	hw := HoltWinters{Season: 4 * step, Bootstrap: 8 * step}
+	from := start.Add(8 * step)
+	end := start.Add(16 * step)
+
+	forecast, err := hw.Forecast(ts1)
+	checkErr(t, err)
+	upper, lower, err := hw.ConfidenceBands(ts1)
+	checkErr(t, err)
+	aberration, err := hw.Aberration(ts1)
+	checkErr(t, err)
+
+	// expected values computed with graphite's holtWintersAnalysis.
+	tss := []struct {
+		Got *ts.TimeSeries
+		Exp *TestSeries
+	}{
+		{
+			Got: forecast,
+			Exp: &TestSeries{
+				Key:   "holtWintersForecast(ts1)",
+				Start: from,
+				End:   end,
+				Step:  step,
+				Data: []float64{1.9946928862328617, 2.6813960317697374, 2.413545863518673, NaN,
+					1.852576980647079, 2.8481169798889328, 2.1269756414020953, 2.24304641031189},
+			},
+		},
+		{
+			Got: upper,
+			Exp: &TestSeries{
+				Key:   "holtWintersConfidenceUpper(ts1)",
+				Start: from,
+				End:   end,
+				Step:  step,
+				Data: []float64{2.457533436489025, 5.453275014274372, 2.413545863518673, NaN,
+					2.5249065700717503, 5.988372970176425, 2.3888829489814665, 8.228661354342936},
+			},
+		},
+		{
+			Got: lower,
+			Exp: &TestSeries{
+				Key:   "holtWintersConfidenceLower(ts1)",
+				Start: from,
+				End:   end,
+				Step:  step,
+				Data: []float64{1.5318523359766982, -0.0904829507348972, 2.413545863518673, NaN,
+					1.180247391222408, -0.29213901039855905, 1.8650683338227239, -3.742568533719157},
+			},
+		},
+		{
+			Got: aberration,
+			Exp: &TestSeries{
+				Key:   "holtWintersAberration(ts1)",
+				Start: from,
+				End:   end,
+				Step:  step,
+				Data: []float64{-0.5318523359766982, 0, 0, 0,
+					-0.18024739122240807, 0, 0.6111170510185335, 11.771338645657064},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		if pair.Got == nil {
+			t.Errorf("FAIL(TimeSeries): can't be nil")
+			continue
+		}
+		checkKey(t, pair.Got.Key(), pair.Exp.Key)
+		checkStart(t, pair.Got.Start(), pair.Exp.Start)
+		checkEnd(t, pair.Got.End(), pair.Exp.End)
+		checkStep(t, pair.Got.Step(), pair.Exp.Step)
+		checkClose(t, pair.Got.Data(), pair.Exp.Data)
+	}
+
+	if _, err := (HoltWinters{Bootstrap: 16 * step}).Forecast(ts1); err == nil {
+		t.Errorf("FAIL(error): the series is not longer than the bootstrap")
+	}
+	if _, err := (HoltWinters{Season: time.Second, Bootstrap: step}).Forecast(ts1); err == nil {
+		t.Errorf("FAIL(error): the season is shorter than the step")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	. "github.com/datacratic/gotsvis/ts"
+)
+
+// HighestCurrent returns the n series with the highest last non NaN value.
+func HighestCurrent(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Highest(n, &Last{})
+}
+
+// HighestAverage returns the n series with the highest average.
+func HighestAverage(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Highest(n, &Average{})
+}
+
+// HighestMax returns the n series with the highest maximum.
+func HighestMax(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Highest(n, &Max{})
+}
+
+// LowestCurrent returns the n series with the lowest last non NaN value.
+func LowestCurrent(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Lowest(n, &Last{})
+}
+
+// LowestAverage returns the n series with the lowest average.
+func LowestAverage(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Lowest(n, &Average{})
+}
+
+// LowestMax returns the n series with the lowest maximum.
+func LowestMax(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Lowest(n, &Max{})
+}
+
+// MostDeviant returns the n series with the highest standard deviation.
+func MostDeviant(tss TimeSeriesSlice, n int) TimeSeriesSlice {
+	return tss.Highest(n, &StdDev{})
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+func TestSelect(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	NaN := math.NaN()
+
+	tss := ts.TimeSeriesSlice{}
+	for _, d := range []struct {
+		Key  string
+		Data []float64
+	}{
+		{"a", []float64{1, 5, 2}},
+		{"b", []float64{3, 3, NaN}},
+		{"c", []float64{NaN, NaN, NaN}},
+		{"d", []float64{0, 10, 0}},
+	} {
+		series, err := ts.NewTimeSeriesOfData(d.Key, start, time.Minute, d.Data)
+		checkErr(t, err)
+		tss = append(tss, *series)
+	}
+
+	tests := []struct {
+		Got ts.TimeSeriesSlice
+		Exp string
+	}{
+		{HighestCurrent(tss, 2), "b,a"},
+		{HighestAverage(tss, 1), "d"},
+		{HighestMax(tss, 2), "d,a"},
+		{LowestCurrent(tss, 1), "d"},
+		{LowestAverage(tss, 2), "a,b"},
+		{LowestMax(tss, 2), "b,a"},
+		{MostDeviant(tss, 1), "d"},
+		{HighestCurrent(tss, 10), "b,a,d,c"},
+	}
+
+	for _, test := range tests {
+		checkKey(t, test.Got.Key(), test.Exp)
+	}
+	checkKey(t, tss.Key(), "a,b,c,d")
+}
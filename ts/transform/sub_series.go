@@ -24,6 +24,17 @@ func SubSeries(ts *TimeSeries, start, end time.Time) *TimeSeries {
 	key := fmt.Sprintf("SubSeries(%s,%s)(%s)", start.Format(time.RFC3339), end.Format(time.RFC3339), ts.Key())
 	step := ts.Step()
 
+	// on the steps of ts the sub series is a view sharing its data.
+	if int(step) != 0 && start.Sub(ts.Start())%step == 0 {
+		if end.Before(start) {
+			return nil
+		}
+		view := ts.View(start, start.Add(end.Sub(start)/step*step))
+		view.SetKey(key)
+		view.SetAlias("")
+		return view
+	}
+
 	newTs, err := NewTimeSeriesOfTimeRange(key, start, end, step, math.NaN())
 	if err != nil {
 		return nil
@@ -5,47 +5,107 @@ package transform
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/datacratic/gotsvis/ts"
 )
 
+// Summarize sums the values of ts in buckets of step. Unlike SummarizeWith,
+// buckets of only NaN values sum to 0.
 func Summarize(ts *TimeSeries, step time.Duration) *TimeSeries {
+	res := SummarizeWith(ts, step, &Sum{}, false)
+	if res == nil {
+		return nil
+	}
+	res.SetKey(fmt.Sprintf("Summarize(%v)(%s)", step, ts.Key()))
+	for t, v := range res.All() {
+		if math.IsNaN(v) {
+			res.SetAt(t, 0)
+		}
+	}
+	return res
+}
 
-	key := fmt.Sprintf("Summarize(%v)(%s)", step, ts.Key())
+// SummarizeWith consolidates the non NaN values of ts in buckets of step with
+// aggregation, buckets without values are NaN. Buckets are aligned on step,
+// or on the start of ts if alignToFrom is set, like graphite's summarize.
+func SummarizeWith(ts *TimeSeries, step time.Duration, aggregation TranformSlice, alignToFrom bool) *TimeSeries {
+	if ts == nil || aggregation == nil || step <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("Summarize(%v,%s)(%s)", step, aggregation.Name(), ts.Key())
+	if alignToFrom {
+		key = fmt.Sprintf("Summarize(%v,%s,true)(%s)", step, aggregation.Name(), ts.Key())
+	}
 	start := ts.Start().Truncate(step)
-	end := ts.End().Truncate(step)
-	if !ts.End().Equal(end) {
-		end = end.Add(step)
+	if alignToFrom {
+		start = ts.Start()
 	}
+	buckets := int(ts.End().Sub(start) / step)
+	if start.Add(time.Duration(buckets) * step).Before(ts.End()) {
+		buckets++
+	}
+	end := start.Add(time.Duration(buckets) * step)
 
 	newTs, err := NewTimeSeriesOfTimeRange(key, start, end, step, math.NaN())
 	if err != nil {
 		return nil
 	}
 
-	var sum float64
-	newC := start
-	for oldC, oldE, oldS := ts.Start(), ts.End(), ts.Step(); oldC.Before(oldE); oldC = oldC.Add(oldS) {
-		if newC.Equal(oldC.Truncate(step)) {
-			v, ok := ts.GetAt(oldC)
-			if !ok || math.IsNaN(v) {
-				continue
-			}
-			sum += v
-		} else {
-			newTs.SetAt(newC, sum)
-			newC = newC.Add(step)
-
-			v, ok := ts.GetAt(oldC)
-			if !ok || math.IsNaN(v) {
-				sum = 0
-				continue
-			}
-			sum = v
+	vals := make([][]float64, buckets)
+	for t, v := range ts.All() {
+		if math.IsNaN(v) {
+			continue
 		}
+		i := int(t.Sub(start) / step)
+		vals[i] = append(vals[i], v)
+	}
+	cursor := start
+	for _, bucket := range vals {
+		if len(bucket) > 0 {
+			newTs.SetAt(cursor, aggregation.TransformSlice(bucket))
+		}
+		cursor = cursor.Add(step)
 	}
-	newTs.SetAt(newC, sum)
-
 	return newTs
 }
+
+// Aggregation returns the TranformSlice for a graphite aggregation function
+// name: sum, avg, min, max, count, first, last, median, stddev, range,
+// multiply or pN for the Nth percentile, like p95.
+func Aggregation(name string) (TranformSlice, error) {
+	switch name {
+	case "sum", "total":
+		return &Sum{}, nil
+	case "avg", "average":
+		return &Average{}, nil
+	case "min":
+		return &Min{}, nil
+	case "max":
+		return &Max{}, nil
+	case "count":
+		return &Count{}, nil
+	case "first":
+		return &First{}, nil
+	case "last", "current":
+		return &Last{}, nil
+	case "median":
+		return &Median{}, nil
+	case "stddev":
+		return &StdDev{}, nil
+	case "range", "rangeOf":
+		return &Range{}, nil
+	case "multiply":
+		return &Multiply{}, nil
+	}
+	if strings.HasPrefix(name, "p") {
+		n, err := strconv.ParseFloat(name[1:], 64)
+		if err == nil && n >= 0 && n <= 100 {
+			return &Percentile{N: n}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown aggregation '%s'", name)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	. "github.com/datacratic/gotsvis/ts"
+)
+
+// CalendarUnit is a bucket of variable duration, like a day that lasts 23 or
+// 25 hours when daylight saving time changes.
+type CalendarUnit int
+
+const (
+	Day CalendarUnit = iota
+	// Week is an ISO week, starting on monday.
+	Week
+	Month
+)
+
+func (unit CalendarUnit) String() string {
+	switch unit {
+	case Day:
+		return "Day"
+	case Week:
+		return "Week"
+	case Month:
+		return "Month"
+	default:
+		return fmt.Sprintf("CalendarUnit(%d)", int(unit))
+	}
+}
+
+// Truncate returns the start of the bucket of t in loc.
+func (unit CalendarUnit) Truncate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	year, month, day := t.Date()
+	switch unit {
+	case Week:
+		monday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-monday, 0, 0, 0, 0, loc)
+	case Month:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	}
+}
+
+// Next returns the start of the bucket following the one starting at t.
+func (unit CalendarUnit) Next(t time.Time) time.Time {
+	switch unit {
+	case Week:
+		return t.AddDate(0, 0, 7)
+	case Month:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// SummarizeCalendar sums the values of ts in calendar buckets of loc. Since
+// the buckets don't have a fixed duration the result is an IrregularSeries
+// with a point at the start of each bucket.
+func SummarizeCalendar(ts *TimeSeries, unit CalendarUnit, loc *time.Location) *IrregularSeries {
+	if ts == nil {
+		return nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	key := fmt.Sprintf("SummarizeCalendar(%s,%s)(%s)", unit, loc, ts.Key())
+	res := NewIrregularSeries(key)
+
+	var bucket, next time.Time
+	var sum float64
+	it := ts.IteratorTimeValue()
+	for t, v, ok := it.Next(); ok; t, v, ok = it.Next() {
+		if bucket.IsZero() || !t.Before(next) {
+			if !bucket.IsZero() {
+				res.Add(bucket, sum)
+			}
+			bucket = unit.Truncate(t, loc)
+			next = unit.Next(bucket)
+			sum = 0
+		}
+		if !math.IsNaN(v) {
+			sum += v
+		}
+	}
+	if !bucket.IsZero() {
+		res.Add(bucket, sum)
+	}
+	return res
+}
@@ -36,6 +36,10 @@ func TestSummarize(t *testing.T) {
 		return
 	}
 
+	tsHole, err := ts.NewTimeSeriesOfData("tsHole", start, 30*time.Minute,
+		[]float64{1, 2, NaN, NaN, 3, 4})
+	checkErr(t, err)
+
 	tss := []struct {
 		Got *ts.TimeSeries
 		Exp *TestSeries
@@ -60,6 +64,16 @@ func TestSummarize(t *testing.T) {
 				Data:  []float64{12, 6},
 			},
 		},
+		{
+			Got: Summarize(tsHole, time.Hour),
+			Exp: &TestSeries{
+				Key:   "Summarize(1h0m0s)(tsHole)",
+				Start: start,
+				End:   start.Add(3 * time.Hour),
+				Step:  time.Hour,
+				Data:  []float64{3, 0, 7},
+			},
+		},
 	}
 
 	for _, pair := range tss {
@@ -68,3 +82,102 @@ func TestSummarize(t *testing.T) {
 	}
 	//t.Errorf("here")
 }
+
+func TestSummarizeWith(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 21, 0, 0, 0, 0, time.UTC)
+	NaN := math.NaN()
+
+	ts1, err := ts.NewTimeSeriesOfData("ts1", start.Add(30*time.Minute), 30*time.Minute,
+		[]float64{1, 2, 3, 4, NaN, 6, NaN, NaN})
+	checkErr(t, err)
+
+	tests := []struct {
+		Aggregation string
+		AlignToFrom bool
+		Exp         *TestSeries
+	}{
+		{"sum", false, &TestSeries{"Summarize(1h0m0s,Sum)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 5, 4, 6, NaN}}},
+		{"avg", false, &TestSeries{"Summarize(1h0m0s,Average)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 2.5, 4, 6, NaN}}},
+		{"max", false, &TestSeries{"Summarize(1h0m0s,Max)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 3, 4, 6, NaN}}},
+		{"min", false, &TestSeries{"Summarize(1h0m0s,Min)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 2, 4, 6, NaN}}},
+		{"count", false, &TestSeries{"Summarize(1h0m0s,Count)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 2, 1, 1, NaN}}},
+		{"first", false, &TestSeries{"Summarize(1h0m0s,First)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 2, 4, 6, NaN}}},
+		{"last", false, &TestSeries{"Summarize(1h0m0s,Last)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 3, 4, 6, NaN}}},
+		{"median", false, &TestSeries{"Summarize(1h0m0s,Median)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 2.5, 4, 6, NaN}}},
+		{"p50", false, &TestSeries{"Summarize(1h0m0s,Percentile(50))(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{1, 3, 4, 6, NaN}}},
+		{"stddev", false, &TestSeries{"Summarize(1h0m0s,StdDev)(ts1)", start, start.Add(5 * time.Hour), time.Hour, []float64{0, 0.5, 0, 0, NaN}}},
+		{"sum", true, &TestSeries{"Summarize(1h0m0s,Sum,true)(ts1)", start.Add(30 * time.Minute), start.Add(270 * time.Minute), time.Hour, []float64{3, 7, 6, NaN}}},
+	}
+
+	for _, test := range tests {
+		aggregation, err := Aggregation(test.Aggregation)
+		checkErr(t, err)
+		got := SummarizeWith(ts1, time.Hour, aggregation, test.AlignToFrom)
+		fmt.Printf("%s\n%s\n\n", got, test.Exp)
+		checkTimeSeries(t, got, test.Exp)
+	}
+
+	if _, err := Aggregation("p101"); err == nil {
+		t.Errorf("FAIL(error): p101 isn't a valid aggregation")
+	}
+}
+
+func TestSummarizeCalendar(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("America/New_York timezone not available:", err)
+	}
+	// midnight the day before the 23 hours day of 2016-03-13.
+	start := time.Date(2016, time.Month(3), 12, 0, 0, 0, 0, ny)
+
+	ts1, err := ts.NewTimeSeriesOfLength("ts1", start.UTC(), time.Hour, 72, 1)
+	checkErr(t, err)
+
+	tests := []struct {
+		Unit   CalendarUnit
+		Times  []time.Time
+		Values []float64
+	}{
+		{
+			Unit: Day,
+			Times: []time.Time{
+				time.Date(2016, time.Month(3), 12, 0, 0, 0, 0, ny),
+				time.Date(2016, time.Month(3), 13, 0, 0, 0, 0, ny),
+				time.Date(2016, time.Month(3), 14, 0, 0, 0, 0, ny),
+				time.Date(2016, time.Month(3), 15, 0, 0, 0, 0, ny),
+			},
+			Values: []float64{24, 23, 24, 1},
+		},
+		{
+			Unit: Week,
+			Times: []time.Time{
+				time.Date(2016, time.Month(3), 7, 0, 0, 0, 0, ny),
+				time.Date(2016, time.Month(3), 14, 0, 0, 0, 0, ny),
+			},
+			Values: []float64{47, 25},
+		},
+		{
+			Unit:   Month,
+			Times:  []time.Time{time.Date(2016, time.Month(3), 1, 0, 0, 0, 0, ny)},
+			Values: []float64{72},
+		},
+	}
+
+	for _, test := range tests {
+		got := SummarizeCalendar(ts1, test.Unit, ny)
+		fmt.Println(got)
+		checkKey(t, got.Key(), fmt.Sprintf("SummarizeCalendar(%s,America/New_York)(ts1)", test.Unit))
+
+		points := got.Points()
+		if len(points) != len(test.Times) {
+			t.Errorf("FAIL(%s): got '%d' buckets, expected '%d'", test.Unit, len(points), len(test.Times))
+			continue
+		}
+		values := []float64{}
+		for i, p := range points {
+			checkStart(t, p.Time, test.Times[i])
+			values = append(values, p.Value)
+		}
+		checkData(t, values, test.Values)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// fillHoles calls fill for every run of NaN in [start, end) of at most maxGap
+// points, or of any length if maxGap is 0.
+func fillHoles(data []float64, maxGap int, fill func(start, end int)) {
+	for i := 0; i < len(data); i++ {
+		if !math.IsNaN(data[i]) {
+			continue
+		}
+		j := i
+		for j < len(data) && math.IsNaN(data[j]) {
+			j++
+		}
+		if maxGap == 0 || j-i <= maxGap {
+			fill(i, j)
+		}
+		i = j
+	}
+}
+
+// ForwardFill replaces holes by the last value before them.
+type ForwardFill struct {
+	MaxGap int
+}
+
+func (ff *ForwardFill) Name() string {
+	return fmt.Sprintf("ForwardFill(%d)", ff.MaxGap)
+}
+
+func (ff *ForwardFill) TransformSeries(data []float64, step time.Duration) {
+	fillHoles(data, ff.MaxGap, func(start, end int) {
+		if start == 0 {
+			return
+		}
+		for i := start; i < end; i++ {
+			data[i] = data[start-1]
+		}
+	})
+}
+
+// BackwardFill replaces holes by the first value after them.
+type BackwardFill struct {
+	MaxGap int
+}
+
+func (bf *BackwardFill) Name() string {
+	return fmt.Sprintf("BackwardFill(%d)", bf.MaxGap)
+}
+
+func (bf *BackwardFill) TransformSeries(data []float64, step time.Duration) {
+	fillHoles(data, bf.MaxGap, func(start, end int) {
+		if end == len(data) {
+			return
+		}
+		for i := start; i < end; i++ {
+			data[i] = data[end]
+		}
+	})
+}
+
+// LinearFill interpolates holes between the values surrounding them, holes at
+// the edges of the series are left as is.
+type LinearFill struct {
+	MaxGap int
+}
+
+func (lf *LinearFill) Name() string {
+	return fmt.Sprintf("LinearFill(%d)", lf.MaxGap)
+}
+
+func (lf *LinearFill) TransformSeries(data []float64, step time.Duration) {
+	fillHoles(data, lf.MaxGap, func(start, end int) {
+		if start == 0 || end == len(data) {
+			return
+		}
+		from, to := data[start-1], data[end]
+		points := float64(end - start + 1)
+		for i := start; i < end; i++ {
+			data[i] = from + (to-from)*float64(i-start+1)/points
+		}
+	})
+}
+
+// MeanFill replaces holes by the mean of the Window non NaN values before them,
+// or of all of them if Window is 0 or less, like MaxGap.
+type MeanFill struct {
+	Window int
+	MaxGap int
+}
+
+func (mf *MeanFill) Name() string {
+	return fmt.Sprintf("MeanFill(%d,%d)", mf.Window, mf.MaxGap)
+}
+
+func (mf *MeanFill) TransformSeries(data []float64, step time.Duration) {
+	// the mean only uses the original values, not the filled ones.
+	orig := make([]float64, len(data))
+	copy(orig, data)
+
+	fillHoles(data, mf.MaxGap, func(start, end int) {
+		var sum float64
+		var count int
+		for i := start - 1; i >= 0 && (mf.Window <= 0 || count < mf.Window); i-- {
+			if !math.IsNaN(orig[i]) {
+				sum += orig[i]
+				count++
+			}
+		}
+		if count == 0 {
+			return
+		}
+		for i := start; i < end; i++ {
+			data[i] = sum / float64(count)
+		}
+	})
+}
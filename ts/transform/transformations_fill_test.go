@@ -0,0 +1,98 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+func TestFillTransforms(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+	NaN := math.NaN()
+
+	tsHoles, err := ts.NewTimeSeriesOfData("tsHoles", start, step,
+		[]float64{NaN, 1, NaN, 3, NaN, NaN, NaN, 7, NaN})
+	checkErr(t, err)
+	if tsHoles == nil {
+		t.Errorf("FAIL(tsHoles): can't be nil, if we want to continue with other tests")
+		return
+	}
+	end := start.Add(9 * step)
+
+	tss := []struct {
+		Got *ts.TimeSeries
+		Exp *TestSeries
+	}{
+		{
+			Got: tsHoles.TransformSeries(&ForwardFill{}),
+			Exp: &TestSeries{
+				Key:   "ForwardFill(0)(tsHoles)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1, 1, 3, 3, 3, 3, 7, 7},
+			},
+		},
+		{
+			Got: tsHoles.TransformSeries(&ForwardFill{MaxGap: 2}),
+			Exp: &TestSeries{
+				Key:   "ForwardFill(2)(tsHoles)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1, 1, 3, NaN, NaN, NaN, 7, 7},
+			},
+		},
+		{
+			Got: tsHoles.TransformSeries(&BackwardFill{MaxGap: 1}),
+			Exp: &TestSeries{
+				Key:   "BackwardFill(1)(tsHoles)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{1, 1, 3, 3, NaN, NaN, NaN, 7, NaN},
+			},
+		},
+		{
+			Got: tsHoles.TransformSeries(&LinearFill{}),
+			Exp: &TestSeries{
+				Key:   "LinearFill(0)(tsHoles)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1, 2, 3, 4, 5, 6, 7, NaN},
+			},
+		},
+		{
+			Got: tsHoles.TransformSeries(&MeanFill{Window: 2, MaxGap: 3}),
+			Exp: &TestSeries{
+				Key:   "MeanFill(2,3)(tsHoles)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1, 1, 3, 2, 2, 2, 7, 5},
+			},
+		},
+		{
+			Got: tsHoles.TransformSeries(&MeanFill{}),
+			Exp: &TestSeries{
+				Key:   "MeanFill(0,0)(tsHoles)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1, 1, 3, 2, 2, 2, 7, 11.0 / 3},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
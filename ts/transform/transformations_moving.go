@@ -0,0 +1,175 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+// Window is the trailing window of the moving transforms, ending with the
+// current point. It is Points long, or Duration long when Points is 0. Points
+// with fewer than MinValid non NaN values in their window are NaN, at least
+// one is always required.
+type Window struct {
+	Points   int
+	Duration time.Duration
+	MinValid int
+}
+
+func (w Window) String() string {
+	s := fmt.Sprintf("%d", w.Points)
+	if w.Points == 0 {
+		s = ts.FormatDuration(w.Duration)
+	}
+	if w.MinValid > 0 {
+		s += fmt.Sprintf(",%d", w.MinValid)
+	}
+	return s
+}
+
+func (w Window) points(step time.Duration) int {
+	if w.Points > 0 {
+		return w.Points
+	}
+	if points := int(w.Duration / step); points > 0 {
+		return points
+	}
+	return 1
+}
+
+// apply replaces every point by aggregate of the non NaN values of its window.
+func (w Window) apply(data []float64, step time.Duration, aggregate func([]float64) float64) {
+	orig := make([]float64, len(data))
+	copy(orig, data)
+
+	points := w.points(step)
+	minValid := w.MinValid
+	if minValid < 1 {
+		minValid = 1
+	}
+	vals := make([]float64, 0, points)
+	for i, _ := range data {
+		vals = vals[:0]
+		for j := i - points + 1; j <= i; j++ {
+			if j >= 0 && !math.IsNaN(orig[j]) {
+				vals = append(vals, orig[j])
+			}
+		}
+		if len(vals) < minValid {
+			data[i] = math.NaN()
+			continue
+		}
+		data[i] = aggregate(vals)
+	}
+}
+
+type MovingAverage struct {
+	Window
+}
+
+func (ma *MovingAverage) Name() string {
+	return fmt.Sprintf("MovingAverage(%s)", ma.Window)
+}
+
+func (ma *MovingAverage) TransformSeries(data []float64, step time.Duration) {
+	ma.apply(data, step, (&Average{}).TransformSlice)
+}
+
+type MovingMedian struct {
+	Window
+}
+
+func (mm *MovingMedian) Name() string {
+	return fmt.Sprintf("MovingMedian(%s)", mm.Window)
+}
+
+func (mm *MovingMedian) TransformSeries(data []float64, step time.Duration) {
+	mm.apply(data, step, (&Median{}).TransformSlice)
+}
+
+type MovingMin struct {
+	Window
+}
+
+func (mm *MovingMin) Name() string {
+	return fmt.Sprintf("MovingMin(%s)", mm.Window)
+}
+
+func (mm *MovingMin) TransformSeries(data []float64, step time.Duration) {
+	mm.apply(data, step, (&Min{}).TransformSlice)
+}
+
+type MovingMax struct {
+	Window
+}
+
+func (mm *MovingMax) Name() string {
+	return fmt.Sprintf("MovingMax(%s)", mm.Window)
+}
+
+func (mm *MovingMax) TransformSeries(data []float64, step time.Duration) {
+	mm.apply(data, step, (&Max{}).TransformSlice)
+}
+
+type MovingStdDev struct {
+	Window
+}
+
+func (ms *MovingStdDev) Name() string {
+	return fmt.Sprintf("MovingStdDev(%s)", ms.Window)
+}
+
+func (ms *MovingStdDev) TransformSeries(data []float64, step time.Duration) {
+	ms.apply(data, step, (&StdDev{}).TransformSlice)
+}
+
+// EWMA is the exponentially weighted moving average with the smoothing factor
+// 2/(n+1) of a window of n points, as graphite's exponentialMovingAverage.
+// NaN are left as is and don't change the average.
+type EWMA struct {
+	Window
+}
+
+func (ewma *EWMA) Name() string {
+	return fmt.Sprintf("EWMA(%s)", ewma.Window)
+}
+
+func (ewma *EWMA) TransformSeries(data []float64, step time.Duration) {
+	orig := make([]float64, len(data))
+	copy(orig, data)
+
+	points := ewma.points(step)
+	alpha := 2 / float64(points+1)
+	minValid := ewma.MinValid
+	if minValid < 1 {
+		minValid = 1
+	}
+
+	avg := math.NaN()
+	// valid is the number of non NaN values in the window ending at i.
+	valid := 0
+	for i, v := range orig {
+		if !math.IsNaN(v) {
+			valid++
+		}
+		if j := i - points; j >= 0 && !math.IsNaN(orig[j]) {
+			valid--
+		}
+		if math.IsNaN(v) {
+			continue
+		}
+		if math.IsNaN(avg) {
+			avg = v
+		} else {
+			avg = alpha*v + (1-alpha)*avg
+		}
+		data[i] = avg
+		if valid < minValid {
+			data[i] = math.NaN()
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+func TestMovingTransforms(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	step := 5 * time.Minute
+	NaN := math.NaN()
+
+	ts1, err := ts.NewTimeSeriesOfData("ts1", start, step,
+		[]float64{1, 2, NaN, 4, 8, NaN, NaN, NaN})
+	checkErr(t, err)
+	if ts1 == nil {
+		t.Errorf("FAIL(ts1): can't be nil, if we want to continue with other tests")
+		return
+	}
+	end := start.Add(8 * step)
+
+	tss := []struct {
+		Got *ts.TimeSeries
+		Exp *TestSeries
+	}{
+		{
+			Got: ts1.TransformSeries(&MovingAverage{Window{Duration: 10 * time.Minute}}),
+			Exp: &TestSeries{
+				Key:   "MovingAverage(10m)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{1, 1.5, 2, 4, 6, 8, NaN, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&MovingAverage{Window{Points: 3, MinValid: 2}}),
+			Exp: &TestSeries{
+				Key:   "MovingAverage(3,2)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1.5, 1.5, 3, 6, 6, NaN, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&MovingMedian{Window{Points: 3}}),
+			Exp: &TestSeries{
+				Key:   "MovingMedian(3)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{1, 1.5, 1.5, 3, 6, 6, 8, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&MovingMin{Window{Points: 3}}),
+			Exp: &TestSeries{
+				Key:   "MovingMin(3)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{1, 1, 1, 2, 4, 4, 8, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&MovingMax{Window{Points: 3}}),
+			Exp: &TestSeries{
+				Key:   "MovingMax(3)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{1, 2, 2, 4, 8, 8, 8, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&MovingStdDev{Window{Duration: 10 * time.Minute}}),
+			Exp: &TestSeries{
+				Key:   "MovingStdDev(10m)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{0, 0.5, 0, 0, 2, 0, NaN, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&EWMA{Window{Points: 3}}),
+			Exp: &TestSeries{
+				Key:   "EWMA(3)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{1, 1.5, NaN, 2.75, 5.375, NaN, NaN, NaN},
+			},
+		},
+		{
+			Got: ts1.TransformSeries(&EWMA{Window{Duration: 15 * time.Minute, MinValid: 2}}),
+			Exp: &TestSeries{
+				Key:   "EWMA(15m,2)(ts1)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 1.5, NaN, 2.75, 5.375, NaN, NaN, NaN},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestMovingKeys(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 0, 0, 0, 0, time.UTC)
+
+	ts1, err := ts.NewTimeSeriesOfLength("ts1", start, time.Hour, 48, 1)
+	checkErr(t, err)
+
+	// window durations are formatted by ts.FormatDuration, in days when whole.
+	for _, test := range []struct {
+		Transform ts.TransformSeries
+		Key       string
+	}{
+		{&MovingAverage{Window{Duration: 24 * time.Hour}}, "MovingAverage(1d)(ts1)"},
+		{&MovingMax{Window{Duration: 90 * time.Minute}}, "MovingMax(1h30m)(ts1)"},
+		{&EWMA{Window{Duration: 2 * time.Hour, MinValid: 1}}, "EWMA(2h,1)(ts1)"},
+	} {
+		checkKey(t, ts1.TransformSeries(test.Transform).Key(), test.Key)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import "fmt"
+
+// The series aggregators combine the series of a TimeSeriesSlice point by
+// point and are named after their graphite functions, so that
+//
+//	tss.TransformSlice(&AverageSeries{})
+//
+// is keyed averageSeries(key1,key2). NaN values are skipped.
+
+type SumSeries struct {
+	Sum
+}
+
+func (s *SumSeries) Name() string {
+	return "sumSeries"
+}
+
+type AverageSeries struct {
+	Average
+}
+
+func (a *AverageSeries) Name() string {
+	return "averageSeries"
+}
+
+type MinSeries struct {
+	Min
+}
+
+func (m *MinSeries) Name() string {
+	return "minSeries"
+}
+
+type MaxSeries struct {
+	Max
+}
+
+func (m *MaxSeries) Name() string {
+	return "maxSeries"
+}
+
+type MedianSeries struct {
+	Median
+}
+
+func (m *MedianSeries) Name() string {
+	return "medianSeries"
+}
+
+// PercentileOfSeries is the N-th percentile using the nearest rank.
+type PercentileOfSeries struct {
+	Percentile
+}
+
+func (p *PercentileOfSeries) Name() string {
+	return fmt.Sprintf("percentileOfSeries(%v)", p.N)
+}
+
+// CountSeries is the number of series with a value, 0 where none has one.
+type CountSeries struct {
+	Count
+}
+
+func (c *CountSeries) Name() string {
+	return "countSeries"
+}
+
+func (c *CountSeries) TransformEmpty() float64 {
+	return 0
+}
+
+type StdDevSeries struct {
+	StdDev
+}
+
+func (s *StdDevSeries) Name() string {
+	return "stddevSeries"
+}
+
+type RangeOfSeries struct {
+	Range
+}
+
+func (r *RangeOfSeries) Name() string {
+	return "rangeOfSeries"
+}
+
+type MultiplySeries struct {
+	Multiply
+}
+
+func (m *MultiplySeries) Name() string {
+	return "multiplySeries"
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datacratic/gotsvis/ts"
+)
+
+func TestSeriesAggregators(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Minute)
+	step := time.Minute
+	NaN := math.NaN()
+
+	a, err := ts.NewTimeSeriesOfData("a", start, step, []float64{1, 2, NaN, 4})
+	checkErr(t, err)
+	b, err := ts.NewTimeSeriesOfData("b", start, step, []float64{3, NaN, NaN, 8})
+	checkErr(t, err)
+	c, err := ts.NewTimeSeriesOfData("c", start, step, []float64{5, 6, NaN, NaN})
+	checkErr(t, err)
+	tss := ts.TimeSeriesSlice{*a, *b, *c}
+
+	tests := []struct {
+		Transform ts.TranformSlice
+		Key       string
+		Data      []float64
+	}{
+		{&SumSeries{}, "sumSeries(a,b,c)", []float64{9, 8, NaN, 12}},
+		{&AverageSeries{}, "averageSeries(a,b,c)", []float64{3, 4, NaN, 6}},
+		{&MinSeries{}, "minSeries(a,b,c)", []float64{1, 2, NaN, 4}},
+		{&MaxSeries{}, "maxSeries(a,b,c)", []float64{5, 6, NaN, 8}},
+		{&MedianSeries{}, "medianSeries(a,b,c)", []float64{3, 4, NaN, 6}},
+		{&PercentileOfSeries{Percentile{N: 50}}, "percentileOfSeries(50)(a,b,c)", []float64{3, 6, NaN, 8}},
+		{&CountSeries{}, "countSeries(a,b,c)", []float64{3, 2, 0, 2}},
+		{&StdDevSeries{}, "stddevSeries(a,b,c)", []float64{math.Sqrt(8.0 / 3), 2, NaN, 2}},
+		{&RangeOfSeries{}, "rangeOfSeries(a,b,c)", []float64{4, 4, NaN, 4}},
+		{&MultiplySeries{}, "multiplySeries(a,b,c)", []float64{15, 12, NaN, 32}},
+	}
+
+	for _, test := range tests {
+		got := tss.TransformSlice(test.Transform)
+		exp := &TestSeries{
+			Key:   test.Key,
+			Start: start,
+			End:   end,
+			Step:  step,
+			Data:  test.Data,
+		}
+		fmt.Printf("%s\n%s\n\n", got, exp)
+		checkTimeSeries(t, got, exp)
+	}
+}
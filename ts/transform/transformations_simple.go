@@ -5,6 +5,7 @@ package transform
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/datacratic/gotsvis/ts"
 )
@@ -123,38 +124,92 @@ func (mark *MarkEqual) Transform(val float64) float64 {
 	return 0.0
 }
 
+// DiffPrevious is the derivative of the series, the difference between a
+// value and the one before it. It is NaN for the first value and for values
+// next to a NaN, as in graphite.
 type DiffPrevious struct {
 	count int64
 	last  float64
 }
 
 func (diff *DiffPrevious) Name() string {
-	return "MarkRaise"
+	return "DiffPrevious"
 }
 
 func (diff *DiffPrevious) Transform(val float64) float64 {
-	panic("not finished")
-
-	if math.IsNaN(val) {
-		return val
+	last := diff.last
+	diff.last = val
+	diff.count++
+	if diff.count == 1 || math.IsNaN(last) {
+		return math.NaN()
 	}
+	return val - last
+}
 
-	if diff.count == 0 {
-		diff.count++
-		diff.last = val
-		return 0.0
+// Maximum values of the usual counters, to be used as MaxValue.
+const (
+	Counter32 = math.MaxUint32
+	Counter64 = math.MaxUint64
+)
+
+// NonNegativeDerivative is DiffPrevious for counters that only go up. When
+// a counter goes down, it wrapped past MaxValue if it was in the upper half
+// of its range, otherwise it was reset and the point is NaN. Every decrease is
+// a reset when MaxValue is 0.
+type NonNegativeDerivative struct {
+	MaxValue float64
+
+	diff DiffPrevious
+}
+
+func (nnd *NonNegativeDerivative) Name() string {
+	return fmt.Sprintf("NonNegativeDerivative(%v)", nnd.MaxValue)
+}
+
+func (nnd *NonNegativeDerivative) Transform(val float64) float64 {
+	last := nnd.diff.last
+	delta := nnd.diff.Transform(val)
+	if math.IsNaN(delta) || delta >= 0 {
+		return delta
 	}
-	diff.count++
-	if diff.last == val {
-		diff.last = val
-		return 0.0
+	if nnd.MaxValue > 0 && val <= nnd.MaxValue && last > nnd.MaxValue/2 {
+		return nnd.MaxValue - last + val + 1
 	}
-	if diff.last-val < 0.0 {
-		diff.last = val
-		return 1.0
+	return math.NaN()
+}
+
+// PerSecond is the NonNegativeDerivative divided by the step in seconds, the
+// rate of a counter.
+type PerSecond struct {
+	MaxValue float64
+}
+
+func (ps *PerSecond) Name() string {
+	return fmt.Sprintf("PerSecond(%v)", ps.MaxValue)
+}
+
+func (ps *PerSecond) TransformSeries(data []float64, step time.Duration) {
+	nnd := &NonNegativeDerivative{MaxValue: ps.MaxValue}
+	for i, v := range data {
+		data[i] = nnd.Transform(v) / step.Seconds()
 	}
-	diff.last = val
-	return 0.0
+}
+
+// Integral is the sum of the values so far, NaN values are left as is.
+type Integral struct {
+	sum float64
+}
+
+func (in *Integral) Name() string {
+	return "Integral"
+}
+
+func (in *Integral) Transform(val float64) float64 {
+	if math.IsNaN(val) {
+		return val
+	}
+	in.sum += val
+	return in.sum
 }
 
 type Transforms []ts.Transform
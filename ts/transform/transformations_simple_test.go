@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -219,6 +220,26 @@ func TestTransforms(t *testing.T) {
 				Data:  []float64{NaN, 0, 0, 0, 1, NaN, 1, 0},
 			},
 		},
+		{
+			Got: tsRaise.Transform(&DiffPrevious{}),
+			Exp: &TestSeries{
+				Key:   "DiffPrevious(tsRaise)",
+				Start: start,
+				End:   start.Add(8 * step),
+				Step:  step,
+				Data:  []float64{NaN, NaN, 0, -1, 1, NaN, NaN, 0},
+			},
+		},
+		{
+			Got: tsRaise.Transform(&Integral{}),
+			Exp: &TestSeries{
+				Key:   "Integral(tsRaise)",
+				Start: start,
+				End:   start.Add(8 * step),
+				Step:  step,
+				Data:  []float64{NaN, 1, 2, 2, 3, NaN, 5, 7},
+			},
+		},
 	}
 
 	for _, pair := range tss {
@@ -227,3 +248,120 @@ func TestTransforms(t *testing.T) {
 	}
 	//t.Errorf("here")
 }
+
+func TestCounterTransforms(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	step := 10 * time.Second
+	end := start.Add(7 * step)
+	NaN := math.NaN()
+
+	tsCounter, err := ts.NewTimeSeriesOfData("tsCounter", start, step,
+		[]float64{10, 30, NaN, 50, 20, Counter32 - 9, 10})
+	checkErr(t, err)
+	if tsCounter == nil {
+		t.Errorf("FAIL(tsCounter): can't be nil, if we want to continue with other tests")
+		return
+	}
+
+	tss := []struct {
+		Got *ts.TimeSeries
+		Exp *TestSeries
+	}{
+		{
+			Got: tsCounter.Transform(&NonNegativeDerivative{}),
+			Exp: &TestSeries{
+				Key:   "NonNegativeDerivative(0)(tsCounter)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 20, NaN, NaN, NaN, Counter32 - 29, NaN},
+			},
+		},
+		{
+			Got: tsCounter.Transform(&NonNegativeDerivative{MaxValue: Counter32}),
+			Exp: &TestSeries{
+				Key:   "NonNegativeDerivative(4.294967295e+09)(tsCounter)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 20, NaN, NaN, NaN, Counter32 - 29, 20},
+			},
+		},
+		{
+			Got: tsCounter.TransformSeries(&PerSecond{MaxValue: Counter32}),
+			Exp: &TestSeries{
+				Key:   "PerSecond(4.294967295e+09)(tsCounter)",
+				Start: start,
+				End:   end,
+				Step:  step,
+				Data:  []float64{NaN, 2, NaN, NaN, NaN, (Counter32 - 29) / 10.0, 2},
+			},
+		},
+	}
+
+	for _, pair := range tss {
+		fmt.Printf("%s\n%s\n\n", pair.Got, pair.Exp)
+		checkTimeSeries(t, pair.Got, pair.Exp)
+	}
+}
+
+func TestSubSeries(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts1, err := ts.NewTimeSeriesOfData("ts1", start, step, []float64{1, 2, 3, 4, 5})
+	checkErr(t, err)
+
+	from, until := start.Add(step), start.Add(4*step)
+	sub := SubSeries(ts1, from, until)
+	checkTimeSeries(t, sub, &TestSeries{
+		Key:   "SubSeries(2016-01-14T10:01:00Z,2016-01-14T10:04:00Z)(ts1)",
+		Start: from,
+		End:   until,
+		Step:  step,
+		Data:  []float64{2, 3, 4},
+	})
+
+	ts1.SetAlias("one")
+	checkKey(t, SubSeries(ts1, from, until).Alias(), sub.Key())
+	ts1.SetAlias("")
+
+	sub.SetAt(from, 20)
+	checkData(t, ts1.Data(), []float64{1, 2, 3, 4, 5})
+
+	offStep := start.Add(30 * time.Second)
+	checkTimeSeries(t, SubSeries(ts1, offStep, until), &TestSeries{
+		Key:   "SubSeries(2016-01-14T10:00:30Z,2016-01-14T10:04:00Z)(ts1)",
+		Start: offStep,
+		End:   offStep.Add(3 * step),
+		Step:  step,
+		Data:  []float64{1, 2, 3},
+	})
+}
+
+// TestSubSeriesConcurrent is meant to be run with -race, SubSeries only reads
+// its input so concurrent calls on the same series are safe.
+func TestSubSeriesConcurrent(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 14, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	ts1, err := ts.NewTimeSeriesOfData("ts1", start, step, []float64{1, 2, 3, 4, 5})
+	checkErr(t, err)
+
+	var wg sync.WaitGroup
+	subs := make([]*ts.TimeSeries, 8)
+	for i, _ := range subs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			subs[i] = SubSeries(ts1, start.Add(step), start.Add(4*step))
+			subs[i].SetAt(start.Add(step), float64(i))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, sub := range subs {
+		checkData(t, sub.Data(), []float64{float64(i), 3, 4})
+	}
+	checkData(t, ts1.Data(), []float64{1, 2, 3, 4, 5})
+}
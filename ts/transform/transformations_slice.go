@@ -2,6 +2,12 @@
 
 package transform
 
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
 type Sum struct {
 }
 
@@ -16,3 +22,202 @@ func (s *Sum) TransformSlice(vals []float64) float64 {
 	}
 	return sum
 }
+
+type Average struct {
+}
+
+func (a *Average) Name() string {
+	return "Average"
+}
+
+func (a *Average) TransformSlice(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+type Max struct {
+}
+
+func (m *Max) Name() string {
+	return "Max"
+}
+
+func (m *Max) TransformSlice(vals []float64) float64 {
+	max := math.Inf(-1)
+	for _, v := range vals {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+type Min struct {
+}
+
+func (m *Min) Name() string {
+	return "Min"
+}
+
+func (m *Min) TransformSlice(vals []float64) float64 {
+	min := math.Inf(1)
+	for _, v := range vals {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+type Last struct {
+}
+
+func (l *Last) Name() string {
+	return "Last"
+}
+
+func (l *Last) TransformSlice(vals []float64) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	return vals[len(vals)-1]
+}
+
+type First struct {
+}
+
+func (f *First) Name() string {
+	return "First"
+}
+
+func (f *First) TransformSlice(vals []float64) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	return vals[0]
+}
+
+type Count struct {
+}
+
+func (c *Count) Name() string {
+	return "Count"
+}
+
+func (c *Count) TransformSlice(vals []float64) float64 {
+	return float64(len(vals))
+}
+
+// Median is the middle value, or the average of the two middle ones, NaN are
+// ignored.
+type Median struct {
+}
+
+func (m *Median) Name() string {
+	return "Median"
+}
+
+func (m *Median) TransformSlice(vals []float64) float64 {
+	sorted := sortedValues(vals)
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Percentile is the value at the N-th percentile using the nearest rank, as in
+// graphite, NaN are ignored.
+type Percentile struct {
+	N float64
+}
+
+func (p *Percentile) Name() string {
+	return fmt.Sprintf("Percentile(%v)", p.N)
+}
+
+func (p *Percentile) TransformSlice(vals []float64) float64 {
+	sorted := sortedValues(vals)
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	rank := int(math.Ceil(p.N / 100 * float64(len(sorted)+1)))
+	if rank <= 0 {
+		return sorted[0]
+	}
+	if rank > len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[rank-1]
+}
+
+// StdDev is the population standard deviation.
+type StdDev struct {
+}
+
+func (s *StdDev) Name() string {
+	return "StdDev"
+}
+
+func (s *StdDev) TransformSlice(vals []float64) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+	var dev float64
+	for _, v := range vals {
+		dev += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(dev / float64(len(vals)))
+}
+
+// Range is the difference between the largest and the smallest value.
+type Range struct {
+}
+
+func (r *Range) Name() string {
+	return "Range"
+}
+
+func (r *Range) TransformSlice(vals []float64) float64 {
+	return (&Max{}).TransformSlice(vals) - (&Min{}).TransformSlice(vals)
+}
+
+type Multiply struct {
+}
+
+func (m *Multiply) Name() string {
+	return "Multiply"
+}
+
+func (m *Multiply) TransformSlice(vals []float64) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	product := 1.0
+	for _, v := range vals {
+		product *= v
+	}
+	return product
+}
+
+func sortedValues(vals []float64) []float64 {
+	sorted := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Float64s(sorted)
+	return sorted
+}
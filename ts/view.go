@@ -0,0 +1,56 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// View returns the part of the series in [start, end) without copying, the
+// view and the series share their data until one of them is modified. The
+// start is moved back to the step containing it and the range is limited to
+// the range of the series.
+func (ts *TimeSeries) View(start, end time.Time) *TimeSeries {
+	first, last := 0, len(ts.data)
+	if start.After(ts.start) {
+		first = int(start.Sub(ts.start) / ts.step)
+	}
+	if end.Before(ts.End()) {
+		last = int((end.Sub(ts.start) + ts.step - 1) / ts.step)
+	}
+	if first > len(ts.data) {
+		first = len(ts.data)
+	}
+	if last < first {
+		last = first
+	}
+
+	view := &TimeSeries{
+		key:    ts.key,
+		start:  ts.start.Add(time.Duration(first) * ts.step),
+		step:   ts.step,
+		data:   ts.data[first:last:last],
+		filler: ts.filler,
+		labels: ts.labels,
+		alias:  ts.alias,
+		shared: ts.shared,
+	}
+	if ts.shared == nil {
+		// a series not built by a constructor has no flag to share, setting
+		// one would modify it so the view gets a copy instead.
+		view.data = view.Data()
+		view.shared = new(atomic.Bool)
+		return view
+	}
+	ts.shared.Store(true)
+	return view
+}
+
+// own copies the data if it is shared with a view.
+func (ts *TimeSeries) own() {
+	if ts.shared != nil && ts.shared.Load() {
+		ts.data = ts.Data()
+		ts.shared = new(atomic.Bool)
+	}
+}
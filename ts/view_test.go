@@ -0,0 +1,40 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package ts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesView(t *testing.T) {
+	start := time.Date(2016, time.Month(1), 25, 10, 0, 0, 0, time.UTC)
+	step := time.Minute
+
+	parent, err := NewTimeSeriesOfData("parent", start, step, []float64{1, 2, 3, 4, 5})
+	checkErr(t, err)
+
+	view := parent.View(start.Add(90*time.Second), start.Add(3*step+time.Second))
+	checkTimeSeries(t, view, &TimeSeries{
+		key: "parent", start: start.Add(step), step: step, data: []float64{2, 3, 4},
+	})
+	if &view.data[0] != &parent.data[1] {
+		t.Errorf("FAIL(view): data should be shared")
+	}
+
+	view.SetAt(start.Add(step), 20)
+	checkData(t, parent.data, []float64{1, 2, 3, 4, 5})
+	checkData(t, view.data, []float64{20, 3, 4})
+
+	other := parent.View(time.Time{}, start.Add(10*step))
+	parent.SetAt(start, 10)
+	checkData(t, parent.data, []float64{10, 2, 3, 4, 5})
+	checkData(t, other.data, []float64{1, 2, 3, 4, 5})
+
+	other.ExtendWith(6)
+	checkData(t, other.data, []float64{1, 2, 3, 4, 5, 6})
+	checkData(t, parent.data, []float64{10, 2, 3, 4, 5})
+
+	empty := parent.View(start.Add(time.Hour), start.Add(2*time.Hour))
+	checkLengthDataEqual(t, empty.data, 0)
+}
@@ -16,9 +16,7 @@ import (
 func timeString(ts *ts.TimeSeries) (string, error) {
 	s := bytes.NewBufferString("[ 'x', ")
 
-	cursor := ts.Start()
-	step := ts.Step()
-	for _, _ = range ts.Data() {
+	for cursor := range ts.All() {
 		if err := s.WriteByte('\''); err != nil {
 			return "", err
 		}
@@ -28,7 +26,6 @@ func timeString(ts *ts.TimeSeries) (string, error) {
 		if _, err := s.WriteString("',"); err != nil {
 			return "", err
 		}
-		cursor = cursor.Add(step)
 	}
 	if s.Len() > 0 {
 		s.Truncate(s.Len() - 1)
@@ -41,7 +38,7 @@ func timeString(ts *ts.TimeSeries) (string, error) {
 
 func valueString(ts *ts.TimeSeries, start, end time.Time) (string, error) {
 	s := bytes.NewBufferString("[ '")
-	if _, err := s.WriteString(ts.Key()); err != nil {
+	if _, err := s.WriteString(ts.Alias()); err != nil {
 		return "", err
 	}
 	if _, err := s.WriteString("', "); err != nil {
@@ -95,6 +92,13 @@ func ChartSlice(tss ts.TimeSeriesSlice) (template.JS, error) {
 	if tss == nil {
 		return template.JS(""), nil
 	}
+	if _, ok := tss.Step(); !ok && len(tss) > 0 {
+		aligned, err := tss.Align(nil)
+		if err != nil {
+			return "", err
+		}
+		tss = aligned
+	}
 	start := tss.Start()
 	end := tss.End()
 	step, ok := tss.Step()
@@ -138,6 +142,16 @@ func Chart(series interface{}) (template.JS, error) {
 		return ChartSingle(t)
 	case ts.TimeSeries:
 		return ChartSingle(&t)
+	case *ts.RollingSeries:
+		if t == nil {
+			return template.JS(""), nil
+		}
+		return ChartSingle(t.TimeSeries())
+	case *ts.ConcurrentSeries:
+		if t == nil {
+			return template.JS(""), nil
+		}
+		return ChartSingle(t.Snapshot())
 	case ts.TimeSeriesSlice:
 		return ChartSlice(t)
 	default:
@@ -151,14 +165,24 @@ func TimeSeriesTagJS(series interface{}) (template.JS, error) {
 		if t == nil {
 			return "", nil
 		}
-		return template.JS(t.Key()), nil
+		return template.JS(t.Alias()), nil
 	case ts.TimeSeries:
+		return template.JS(t.Alias()), nil
+	case *ts.RollingSeries:
+		if t == nil {
+			return "", nil
+		}
 		return template.JS(t.Key()), nil
-	case ts.TimeSeriesSlice:
+	case *ts.ConcurrentSeries:
 		if t == nil {
 			return "", nil
 		}
 		return template.JS(t.Key()), nil
+	case ts.TimeSeriesSlice:
+		if t == nil {
+			return "", nil
+		}
+		return t.JSTag(), nil
 	default:
 		return "", fmt.Errorf("unknown type '%T'", series)
 	}